@@ -0,0 +1,140 @@
+package main
+
+import (
+	"fmt"
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten"
+	"github.com/hajimehoshi/ebiten/text"
+	"github.com/jakestanley/golang-civclone/sim"
+)
+
+// BuildingsUi lets the player pick what clicking an empty tile builds
+type BuildingsUi struct {
+	window  *Window
+	focused bool
+	// chosen is the ID of the settlement kind that gets placed
+	chosen string
+	// kindButtons are the kinds that can be built right now
+	kindButtons []*Button
+	kinds       []*sim.SettlementKind
+}
+
+var buildingsUi BuildingsUi
+
+func CreateBuildingsUi() {
+
+	buildingsUi.focused = true
+	buildingsUi.window.redraw = true
+	buildingsUi.window.buttons = []*Button{}
+	buildingsUi.kindButtons = []*Button{}
+	buildingsUi.kinds = []*sim.SettlementKind{}
+
+	for _, kind := range state.BuildableKinds() {
+		id := kind.ID
+		name := kind.Name
+		b, _ := CreateButton(&btn, name, 0, 0)
+		b.executable = true
+		b.selected = buildingsUi.chosen == id
+		b.exec = func() string {
+			buildingsUi.chosen = id
+			RefreshBuildingsUi()
+			return fmt.Sprintf("Chose %s", name)
+		}
+		b.SetWindow(buildingsUi.window)
+		buildingsUi.kindButtons = append(buildingsUi.kindButtons, b)
+		buildingsUi.kinds = append(buildingsUi.kinds, kind)
+	}
+}
+
+func ClearBuildingsUi() {
+	buildingsUi.focused = false
+	buildingsUi.kindButtons = []*Button{}
+	buildingsUi.window.Destroy()
+}
+
+// ToggleBuildingsUi opens the buildings window, or closes it if it's open
+func ToggleBuildingsUi() string {
+	if buildingsUi.focused {
+		ClearBuildingsUi()
+		return "Closed buildings"
+	}
+	CreateBuildingsUi()
+	return "Opened buildings"
+}
+
+// RefreshBuildingsUi recreates the buildings UI, if it's open, to reflect
+// 	what can be built now
+func RefreshBuildingsUi() {
+	if buildingsUi.focused {
+		ClearBuildingsUi()
+		CreateBuildingsUi()
+	}
+}
+
+// PlaceBuilding builds the chosen kind of settlement on the empty square at
+// 	x,y, if it can be built
+func PlaceBuilding(x, y int) {
+
+	kind, ok := sim.SettlementKinds[buildingsUi.chosen]
+	if !ok {
+		messages.AddMessage("Choose something to build from the buildings window")
+		return
+	}
+	if _, err := state.StartConstruction(kind, x, y); err != nil {
+		messages.AddMessage(fmt.Sprintf("Can't build %s: %s", kind.Name, err))
+		return
+	}
+
+	// unique buildings can't be chosen again
+	RefreshBuildingsUi()
+}
+
+func DrawBuildingsUi(screen *ebiten.Image) {
+
+	if !buildingsUi.focused {
+		return
+	}
+
+	if buildingsUi.window.redraw || buildingsUi.window.canvas == nil {
+
+		width := buildingsUi.window.width
+		height := buildingsUi.window.height
+
+		canvas := ebiten.NewImage(width, height)
+		canvas.Fill(color.Black)
+
+		titleText := "Buildings"
+		titleWidth := text.BoundString(fontTitle, titleText).Dx()
+		text.Draw(canvas, titleText, fontTitle, width/2-titleWidth/2, 20, color.White)
+
+		x := 4
+		y := 40
+
+		if len(buildingsUi.kindButtons) == 0 {
+			text.Draw(canvas, "Nothing can be built", fontDetail, x, y, color.White)
+		}
+
+		// each kind gets a button, with what it takes and gives beneath
+		for i, b := range buildingsUi.kindButtons {
+			kind := buildingsUi.kinds[i]
+			b.DrawButtonAt(canvas, x, y)
+			y += 22
+
+			detail := fmt.Sprintf("effort %.2f, popcap %d, needs %s", kind.Effort, kind.Popcap, kind.Requirements())
+			text.Draw(canvas, detail, fontSmall, x, y, color.Gray{Y: 160})
+			y += 10
+			text.Draw(canvas, fmt.Sprintf("cost %s", kind.Cost.Describe()), fontSmall, x, y, color.Gray{Y: 160})
+			y += 8
+		}
+
+		buildingsUi.window.canvas = canvas
+	}
+
+	ops := &ebiten.DrawImageOptions{}
+	ops.ColorM.Scale(1, 1, 1, 0.95)
+	ops.GeoM.Translate(buildingsUi.window.px, buildingsUi.window.py)
+	screen.DrawImage(buildingsUi.window.canvas, ops)
+
+	buildingsUi.window.redraw = false
+}
@@ -0,0 +1,144 @@
+package main
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten"
+	"github.com/hajimehoshi/ebiten/text"
+	"github.com/jakestanley/golang-civclone/sim"
+)
+
+// ConstructionUi shows the construction queue of the settlement being
+// 	managed, under the settlement window
+type ConstructionUi struct {
+	window  *Window
+	focused bool
+	// queue is the settlement's construction queue, with a row of buttons
+	// 	for each entry
+	queue   []sim.Construction
+	buttons [][]*Button
+}
+
+var constructionUi ConstructionUi
+
+func CreateConstructionUi() {
+
+	constructionUi.focused = true
+	constructionUi.window.redraw = true
+	constructionUi.window.buttons = []*Button{}
+	constructionUi.buttons = [][]*Button{}
+
+	s := world.squares[settlementUi.sx][settlementUi.sy].Settlement
+	state.SyncQueue(s)
+	constructionUi.queue = s.Queue
+
+	for _, entry := range s.Queue {
+		target := entry.Target
+		workers := entry.Workers
+
+		// raise priority, then more or fewer citizens
+		actions := []struct {
+			label string
+			do    func() error
+		}{
+			{"^", func() error { return state.Prioritise(s, target) }},
+			{"+", func() error { return state.SetWorkers(s, target, workers+1) }},
+			{"-", func() error { return state.SetWorkers(s, target, workers-1) }},
+		}
+
+		row := []*Button{}
+		for _, a := range actions {
+			do := a.do
+			b, _ := CreateButton(&btn, a.label, 0, 0)
+			b.executable = true
+			b.exec = func() string {
+				if err := do(); err != nil {
+					messages.AddMessage(fmt.Sprintf("Can't change construction: %s", err))
+					return err.Error()
+				}
+				RefreshSettlementUi()
+				return fmt.Sprintf("Changed construction at %d,%d", target.X, target.Y)
+			}
+			b.SetWindow(constructionUi.window)
+			row = append(row, b)
+		}
+		constructionUi.buttons = append(constructionUi.buttons, row)
+	}
+}
+
+func ClearConstructionUi() {
+	constructionUi.focused = false
+	constructionUi.buttons = [][]*Button{}
+	constructionUi.window.Destroy()
+}
+
+// ConstructionLabel describes a construction with its progress, how much it
+// 	will make next turn and how many turns it has left, from this year's
+// 	forecast
+func ConstructionLabel(s *sim.Settlement, forecast map[*sim.Settlement]float64) string {
+
+	words := fmt.Sprintf("%.1f %% +%.1f", s.Progress*100, forecast[s]*100)
+	if turns := state.TurnsToComplete(s, forecast); turns > 0 {
+		words += fmt.Sprintf(" (%dy)", turns)
+	}
+	return words
+}
+
+func DrawConstructionUi(screen *ebiten.Image) {
+
+	if !constructionUi.focused {
+		return
+	}
+
+	if constructionUi.window.redraw || constructionUi.window.canvas == nil {
+
+		width := constructionUi.window.width
+		height := constructionUi.window.height
+
+		canvas := ebiten.NewImage(width, height)
+		canvas.Fill(color.Black)
+
+		titleText := "Construction"
+		titleWidth := text.BoundString(fontTitle, titleText).Dx()
+		text.Draw(canvas, titleText, fontTitle, width/2-titleWidth/2, 20, color.White)
+
+		x := 4
+		y := 40
+
+		if len(constructionUi.queue) == 0 {
+			text.Draw(canvas, "Nothing being built nearby", fontDetail, x, y, color.White)
+		}
+
+		home := image.Point{X: settlementUi.sx, Y: settlementUi.sy}
+		forecast := state.ConstructionForecast()
+		for i, entry := range constructionUi.queue {
+			s := world.squares[entry.Target.X][entry.Target.Y].Settlement
+
+			workers := "shared"
+			if entry.Workers > 0 {
+				workers = fmt.Sprintf("%d citizens", entry.Workers)
+			}
+			label := fmt.Sprintf("%d. %s %s, %s", i+1, s.Kind.Name, Direction(home, entry.Target), workers)
+			text.Draw(canvas, label, fontDetail, x, y, color.White)
+			text.Draw(canvas, ConstructionLabel(s, forecast), fontSmall, x, y+10, color.Gray{Y: 160})
+
+			bx := width - 80
+			for _, b := range constructionUi.buttons[i] {
+				b.DrawButtonAt(canvas, bx, y-12)
+				bx += 24
+			}
+			y += 24
+		}
+
+		constructionUi.window.canvas = canvas
+	}
+
+	ops := &ebiten.DrawImageOptions{}
+	ops.ColorM.Scale(1, 1, 1, 0.95)
+	ops.GeoM.Translate(constructionUi.window.px, constructionUi.window.py)
+	screen.DrawImage(constructionUi.window.canvas, ops)
+
+	constructionUi.window.redraw = false
+}
@@ -0,0 +1,143 @@
+package main
+
+import (
+	"fmt"
+	"image/color"
+	"strings"
+
+	"github.com/hajimehoshi/ebiten"
+	"github.com/hajimehoshi/ebiten/text"
+	"github.com/jakestanley/golang-civclone/sim"
+)
+
+// DemolishUi asks the player to confirm demolishing a settlement
+type DemolishUi struct {
+	window  *Window
+	focused bool
+	target  *sim.Settlement
+	confirm *Button
+	cancel  *Button
+}
+
+var demolishUi DemolishUi
+
+// CreateDemolishUi asks about demolishing the settlement at x,y, if there
+// 	is one
+func CreateDemolishUi(x, y int) {
+
+	ClearDemolishUi()
+
+	s := world.squares[x][y].Settlement
+	if s == nil {
+		return
+	}
+	if err := state.CanDemolish(s); err != nil {
+		messages.AddMessage(fmt.Sprintf("Can't demolish: %s", err))
+		return
+	}
+
+	demolishUi.focused = true
+	demolishUi.target = s
+	demolishUi.window.redraw = true
+	demolishUi.window.buttons = []*Button{}
+
+	demolishUi.confirm, _ = CreateButton(&btn, "Demolish", 0, 0)
+	demolishUi.confirm.executable = true
+	demolishUi.confirm.exec = DemolishTarget
+	demolishUi.confirm.SetWindow(demolishUi.window)
+
+	demolishUi.cancel, _ = CreateButton(&btn, "Cancel", 0, 0)
+	demolishUi.cancel.executable = true
+	demolishUi.cancel.exec = func() string {
+		ClearDemolishUi()
+		return "Cancelled demolition"
+	}
+	demolishUi.cancel.SetWindow(demolishUi.window)
+}
+
+func ClearDemolishUi() {
+	if demolishUi.focused {
+		demolishUi.window.Destroy()
+	}
+	demolishUi.focused = false
+	demolishUi.target = nil
+}
+
+// DemolishTarget demolishes the settlement the player confirmed
+func DemolishTarget() string {
+
+	s := demolishUi.target
+	ClearDemolishUi()
+	if s == nil {
+		return "Nothing to demolish"
+	}
+
+	if settlementUi.focused && settlementUi.sx == s.WorldX && settlementUi.sy == s.WorldY {
+		DefocusSettlement()
+	}
+
+	d, err := state.Demolish(s)
+	if err != nil {
+		messages.AddMessage(fmt.Sprintf("Can't demolish: %s", err))
+		return err.Error()
+	}
+
+	messages.AddMessage(fmt.Sprintf("Demolished the %s, got back %s", s.Kind.Name, d.Refund.Describe()))
+	if len(d.Rehoused) > 0 {
+		messages.AddMessage(fmt.Sprintf("Rehoused %s", strings.Join(d.Rehoused, ", ")))
+	}
+	if len(d.Homeless) > 0 {
+		messages.AddMessage(fmt.Sprintf("%s had nowhere to live and moved in homeless", strings.Join(d.Homeless, ", ")))
+	}
+	if len(d.Lost) > 0 {
+		messages.AddMessage(fmt.Sprintf("%s had nowhere to live and left", strings.Join(d.Lost, ", ")))
+	}
+	if len(d.Unassigned) > 0 {
+		messages.AddMessage(fmt.Sprintf("%s had nothing left to do there and are now idle", strings.Join(d.Unassigned, ", ")))
+	}
+
+	RefreshSettlementUi()
+	RefreshBuildingsUi()
+	return fmt.Sprintf("Demolished %d,%d", s.WorldX, s.WorldY)
+}
+
+func DrawDemolishUi(screen *ebiten.Image) {
+
+	if !demolishUi.focused {
+		return
+	}
+
+	if demolishUi.window.redraw || demolishUi.window.canvas == nil {
+
+		width := demolishUi.window.width
+		height := demolishUi.window.height
+		s := demolishUi.target
+
+		canvas := ebiten.NewImage(width, height)
+		canvas.Fill(color.Black)
+
+		titleText := fmt.Sprintf("Demolish %s?", s.Kind.Name)
+		titleWidth := text.BoundString(fontTitle, titleText).Dx()
+		text.Draw(canvas, titleText, fontTitle, width/2-titleWidth/2, 20, color.White)
+
+		x := 4
+		y := 40
+		text.Draw(canvas, fmt.Sprintf("Refund: %s", state.Refund(s).Describe()), fontDetail, x, y, color.White)
+		if len(s.Citizens) > 0 {
+			y += 14
+			text.Draw(canvas, fmt.Sprintf("%d citizens will need rehousing", len(s.Citizens)), fontDetail, x, y, color.White)
+		}
+
+		demolishUi.confirm.DrawButtonAt(canvas, x, height-24)
+		demolishUi.cancel.DrawButtonAt(canvas, x+80, height-24)
+
+		demolishUi.window.canvas = canvas
+	}
+
+	ops := &ebiten.DrawImageOptions{}
+	ops.ColorM.Scale(1, 1, 1, 0.95)
+	ops.GeoM.Translate(demolishUi.window.px, demolishUi.window.py)
+	screen.DrawImage(demolishUi.window.canvas, ops)
+
+	demolishUi.window.redraw = false
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"fmt"
+	"image/color"
+	"os"
+
+	"github.com/hajimehoshi/ebiten"
+	"github.com/hajimehoshi/ebiten/inpututil"
+	"github.com/hajimehoshi/ebiten/text"
+	"github.com/jakestanley/golang-civclone/sim"
+)
+
+// endScreenTitles are the headings of the end screen for each outcome
+var endScreenTitles = map[string]string{
+	sim.OutcomeVictory:     "Your people have left for the stars",
+	sim.OutcomeExtinction:  "Your people are no more",
+	sim.OutcomeCatastrophe: "The world has ended",
+}
+
+// endScreen is the drawn summary of the finished game. It's drawn once, as
+// 	nothing changes once the game is over, and cleared on loading
+var endScreen *ebiten.Image
+
+// LaunchSpaceship ends the game, if the spaceship is ready
+func LaunchSpaceship() string {
+	if err := state.Launch(); err != nil {
+		messages.AddMessage(fmt.Sprintf("Can't launch: %s", err))
+		return err.Error()
+	}
+	DefocusSettlement()
+	ClearResearchUi()
+	ClearBuildingsUi()
+	ClearDemolishUi()
+	return "Launched the spaceship"
+}
+
+// UpdateLaunchButton enables the launch button once the spaceship is ready
+func UpdateLaunchButton() {
+	b := SButtons[BtnLaunch]
+	disabled := state.CanLaunch() != nil
+	if b.disabled != disabled {
+		b.disabled = disabled
+		b.SetRedraw()
+	}
+}
+
+// UpdateEndScreen handles input once the game is over. The finished game
+// 	isn't saved on the way out so it doesn't replace the quicksave
+func UpdateEndScreen() {
+
+	if inpututil.IsKeyJustPressed(ebiten.KeyEscape) {
+		fmt.Println("Thanks for playing")
+		os.Exit(0)
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyF9) {
+		LoadGame(QuickSavePath)
+	}
+}
+
+// DrawEndScreen draws the summary of a finished game over everything else
+func DrawEndScreen(screen *ebiten.Image) {
+
+	if !state.Over() {
+		return
+	}
+
+	if endScreen == nil {
+		width, height := screen.Size()
+		endScreen = ebiten.NewImage(width, height)
+		drawEndScreenCanvas(endScreen)
+	}
+	screen.DrawImage(endScreen, &ebiten.DrawImageOptions{})
+}
+
+// drawEndScreenCanvas draws the summary onto canvas
+func drawEndScreenCanvas(canvas *ebiten.Image) {
+
+	summary := state.Summary()
+	width, _ := canvas.Size()
+
+	canvas.Fill(color.RGBA{A: 230})
+
+	title := endScreenTitles[summary.Outcome]
+	titleWidth := text.BoundString(fontTitle, title).Dx()
+	text.Draw(canvas, title, fontTitle, width/2-titleWidth/2, 60, color.White)
+
+	lines := []string{}
+	if summary.Cause != "" {
+		lines = append(lines, summary.Cause, "")
+	}
+	lines = append(lines,
+		fmt.Sprintf("Years: %d", summary.Years),
+		fmt.Sprintf("Age reached: %s", sim.Epochs[state.Epoch]),
+		fmt.Sprintf("Citizens: %d (peak %d)", summary.Population, summary.PeakPopulation),
+		fmt.Sprintf("Births: %d", summary.Births),
+		fmt.Sprintf("Deaths: %d", summary.Deaths),
+		"Resources spent:",
+	)
+	for _, id := range sim.ResourceOrder {
+		lines = append(lines, fmt.Sprintf("  %s: %.1f", sim.Resources[id].Name, summary.Spent[id]))
+	}
+	lines = append(lines, "", "Escape to quit, F9 to load your last save")
+
+	y := 90
+	for _, l := range lines {
+		text.Draw(canvas, l, fontDetail, width/2-100, y, color.White)
+		y += 14
+	}
+}
@@ -10,15 +10,9 @@ import (
 	"github.com/hajimehoshi/ebiten/ebitenutil"
 )
 
+// icons are indexed by sim.Resource Icon, i.e sim.IconFood
 var icons []*ebiten.Image
 
-const IconsUranium = 0
-const IconsOil = 1
-const IconsFood = 2
-const IconsMedicine = 3
-const IconsWood = 4
-const IconsStone = 5
-
 func LoadTileSprite(path string) TileSprite {
 	flat, _, err := ebitenutil.NewImageFromFile(filepath.Join(path, "flat.png"))
 	if err != nil {
@@ -1,14 +1,14 @@
 package main
 
 import (
+	"flag"
 	"fmt"
 	"image"
 	"image/color"
 	"log"
-	"math/rand"
 	"os"
 	"path/filepath"
-	"strings"
+	"time"
 
 	"github.com/hajimehoshi/ebiten/ebitenutil"
 	"github.com/hajimehoshi/ebiten/inpututil"
@@ -18,22 +18,11 @@ import (
 	_ "image/png"
 
 	"github.com/hajimehoshi/ebiten"
+	"github.com/jakestanley/golang-civclone/sim"
 )
 
 type Game struct{}
 
-type Citizen struct {
-	name          string
-	age           int
-	gender        string
-	education     int
-	genetics      int
-	assignment    *image.Point
-	proficiencies map[string]float64
-	// TODO home settlement, tile on last turn
-	// home settlement could provide a buff to effort
-}
-
 // UI elements
 type UiSprite struct {
 	left *ebiten.Image
@@ -66,12 +55,19 @@ type SettlementUi struct {
 	redraw bool
 	// selectCtzButtons
 	selectCtzButtons []*Button
-	selectedCtz      *Citizen
+	selectedCtz      *sim.Citizen
+	// ineligibleReasons explain why greyed out citizens can't be selected
+	ineligibleReasons []string
+	// selectedID is the ID of the selected citizen, so the selection can
+	// 	survive the window being recreated
+	selectedID int
 	// selectJobButtons
 	selectJobButtons []*Button
-	selectedJob      *Job
-	// jobs
-	jobs []*Job
+	// jobs are the jobs available to the settlement's citizens, in the same
+	// 	order as selectJobButtons. The last button is for making them idle
+	jobs []sim.Job
+	// travellers describe the citizens on their way to the settlement
+	travellers []string
 }
 
 type Button struct {
@@ -107,16 +103,12 @@ type Input struct {
 	hovered  bool
 }
 
+// Square is the render state of a sim square
 type Square struct {
-	kind        int
-	building    int
+	*sim.Square
 	moved       bool
-	height      int
-	liquid      bool
 	highlighted bool
 	input       *Input
-	settlement  *Settlement
-	resource    *ResourceType
 	tile        *Tile
 	// Texture is an indicator as to which image to use to render the tile.
 	// 	When loading a renderer, you will need to provide tile resources,
@@ -125,24 +117,6 @@ type Square struct {
 	Texture string
 }
 
-// HasCompletedSettlement returns true if this square has a settlement that
-// 	has completed construction
-func (s *Square) HasCompletedSettlement() bool {
-	if s.settlement == nil {
-		return false
-	}
-
-	return s.settlement.completed
-}
-
-func (s *Square) HasResource() bool {
-	return s.resource != nil
-}
-
-func (s *Square) IsEmpty() bool {
-	return s.settlement == nil && s.resource == nil
-}
-
 // TODO TileType struct?
 type TileSprite struct {
 	flat     *ebiten.Image
@@ -165,58 +139,13 @@ func (a *Animation) Animate() {
 	}
 }
 
-// World
-type SettlementKind struct {
-	name      string
-	effort    float64
-	animation Animation
-	nothing   bool
-	popcap    int
-}
-
-type Stocks struct {
-	wood float64
-}
-
-type ResourceType struct {
-	name      string
-	animation Animation
-}
-
-type Settlement struct {
-	// TODO CONSIDER whether or not this is duplication
-	worldX, worldY int
-	kind           *SettlementKind
-	progress       float64
-	completed      bool
-	citizens       []Citizen
-}
-
-// TODO remove as for now Point does this well enough
-type Work struct {
-	x, y int
-}
-
-type Job struct {
-	x, y int
-	kind string
-	work *Work
-}
-
+// World is the render state of the sim world
 type World struct {
-	squares     [][]Square
-	settlements []*Settlement
-	xOffset     int
-	yOffset     int
-	redraw      bool
-	cachedImg   *ebiten.Image
-}
-
-type Research struct {
-	// Husbandry when researched, allows moving a citizen to an adjacent tile without having to wait a turn
-	Husbandry bool
-	// Transit when researched, allows moving a citizen to any tile without having to wait a turn
-	Transit bool
+	squares   [][]Square
+	xOffset   int
+	yOffset   int
+	redraw    bool
+	cachedImg *ebiten.Image
 }
 
 const (
@@ -227,10 +156,6 @@ const (
 	WindowWidth = 1024
 	// WindowHeight is the minimum supported window height
 	WindowHeight = 768
-	// TWater water tile type index
-	TWater = 0
-	// TGrass grass tile type index
-	TGrass = 1
 	// TileWidth width of tiles in pixels (unscaled)
 	TileWidth = 64
 	// TileHeight height of tiles in pixels (unscaled)
@@ -238,21 +163,28 @@ const (
 	// BtnEndTurn is the button map key for ending a turn
 	BtnEndTurn       = "END_TURN"
 	BtnShowBuildings = "SHOW_BUILDINGS"
-	// resource type refs
-	rtForest = "forest"
+	// BtnResearch is the button map key for opening the research window
+	BtnResearch = "RESEARCH"
+	// BtnLaunch is the button map key for launching the spaceship
+	BtnLaunch = "LAUNCH"
+	// QuickSavePath is where F5 saves to and F9 loads from
+	QuickSavePath = "quicksave.json.gz"
 )
 
 var (
 
 	// constant vars (they're vars but we treat them as constants. see defs())
-	settlementKinds map[string]*SettlementKind
-	resourcesTypes  map[string]*ResourceType
+	settlementAnimations map[string]*Animation
+	resourceAnimations   map[string]*Animation
 
-	nothing     Settlement
 	tileSprites map[string]TileSprite
 
 	// meta game state
 	initialised bool
+	// seed for new games, see -seed
+	seed int64
+	// world generation parameters for new games, see -width, -shape, etc
+	worldOptions sim.WorldOptions
 
 	// world images
 	tilesLayer     *ebiten.Image
@@ -272,11 +204,8 @@ var (
 	sWidth    int
 	lastFrame int = 0
 	ticks     int = 0
-	year      int = 1
-	epoch     int = 0
-	stocks    Stocks
+	state     *sim.GameState
 	world     World
-	research  Research
 	north     *ebiten.Image
 	highlight *ebiten.Image
 
@@ -309,36 +238,6 @@ var (
 	renderThingsLayer bool
 )
 
-// CanInteractWithTile returns true if this or a neighbouring tile has a
-// 	completed settlement
-func CanInteractWithTile(x, y int) bool {
-
-	if x < 0 {
-		return false
-	}
-
-	// TODO check this works for a non-square world
-	return world.squares[x][y].HasCompletedSettlement() ||
-		(x > 0 && world.squares[x-1][y].HasCompletedSettlement()) ||
-		(y > 0 && world.squares[x][y-1].HasCompletedSettlement()) ||
-		(x < len(world.squares)-1 && world.squares[x+1][y].HasCompletedSettlement()) ||
-		(x < len(world.squares) && y < len(world.squares[x])-1 && world.squares[x][y+1].HasCompletedSettlement())
-}
-
-// TileIsInRange returns true if coordinates are valid map coordinates
-func TileIsInRange(x, y int) bool {
-
-	return image.Point{X: x, Y: y}.In(
-		image.Rectangle{
-			Min: image.Point{
-				X: 0, Y: 0,
-			},
-			Max: image.Point{
-				X: len(world.squares), Y: len(world.squares[0]),
-			},
-		})
-}
-
 // ResetFrameState is a handy function that will reset any variables that
 // 	should not persist between updates, i.e mouse over, button hovers, etc
 func ResetFrameState() {
@@ -360,7 +259,7 @@ func ResetFrameState() {
 	}
 
 	// unhover any tiles
-	if TileIsInRange(mtx, mty) {
+	if state.World.TileIsInRange(mtx, mty) {
 		world.squares[mtx][mty].input.hovered = false
 	}
 
@@ -373,9 +272,10 @@ func ResetFrameState() {
 // 	the render logic. Returns coordinates of square where the mouse is.
 func UpdateDrawLocations() (int, int) {
 
-	// north will be top left
-	xOffset := 0
-	yOffset := 120
+	// north will be top left. the world is pushed down so that the northern
+	// 	corner, which is drawn highest, starts at the top of the screen
+	xOffset := world.xOffset
+	yOffset := world.yOffset + 8 + (state.World.Height()-1)*16
 
 	mouseX, mouseY := -1, -1
 
@@ -402,7 +302,7 @@ func UpdateDrawLocations() (int, int) {
 				// Recalculating tile position on screen
 				tx = float64(xOffset) + float64(y*32) + float64(x*32)
 				ty = float64(yOffset) - float64(16*y) + float64(x*16)
-				ty = ty - float64(square.height)
+				ty = ty - float64(square.Height)
 
 				// used for mouse finding
 				tile.innerBounds = image.Rectangle{
@@ -443,10 +343,12 @@ func UpdateDrawLocations() (int, int) {
 }
 
 func DefocusSettlement() {
+	settlementUi.selectedID = 0
 	if settlementUi.focused {
 		HighlightAvailableTiles(settlementUi.sx, settlementUi.sy, false)
 		ClearSettlementUi()
 	}
+	ZoomOut()
 }
 
 // TODO consider making this "select settlement or something. focused might be a bit ambiguous"
@@ -469,8 +371,7 @@ func FocusSettlement(x, y int) bool {
 	return true
 }
 
-// WASD moves the cursor input. Currently the boundaries are hardcoded
-// 	TODO unhardcode these boundaries
+// WASD moves the cursor input within the bounds of the world
 func WASD() {
 
 	// move cursor north
@@ -481,7 +382,7 @@ func WASD() {
 	}
 	// move cursor south
 	if inpututil.IsKeyJustPressed(ebiten.KeyS) {
-		if ctx < 7 {
+		if ctx < state.World.Width()-1 {
 			ctx++
 		}
 	}
@@ -492,16 +393,83 @@ func WASD() {
 		}
 	}
 	if inpututil.IsKeyJustPressed(ebiten.KeyD) {
-		if cty < 7 {
+		if cty < state.World.Height()-1 {
 			cty++
 		}
 	}
 	if inpututil.IsKeyJustPressed(ebiten.KeyEscape) {
+		// save on the way out so the game can be picked up next lunch time
+		SaveGame(QuickSavePath)
 		fmt.Println("Thanks for playing")
 		os.Exit(0)
 	}
 }
 
+// PanCamera scrolls the world with the arrow keys, for worlds that don't fit
+// 	on the screen
+func PanCamera() {
+
+	dx, dy := 0, 0
+	if inpututil.IsKeyJustPressed(ebiten.KeyLeft) {
+		dx += TileWidth / 2
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyRight) {
+		dx -= TileWidth / 2
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyUp) {
+		dy += TileHeight / 2
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyDown) {
+		dy -= TileHeight / 2
+	}
+
+	if dx != 0 || dy != 0 {
+		world.Pan(dx, dy)
+	}
+}
+
+// Pan moves the world on screen and marks every square to be repositioned
+func (w *World) Pan(dx, dy int) {
+	w.xOffset += dx
+	w.yOffset += dy
+	for x := 0; x < len(w.squares); x++ {
+		for y := 0; y < len(w.squares[x]); y++ {
+			w.squares[x][y].moved = true
+		}
+	}
+	w.redraw = true
+}
+
+// SaveGame saves the current game to path, reporting the outcome as a message
+func SaveGame(path string) {
+	if err := state.SaveFile(path); err != nil {
+		log.Println(err)
+		messages.AddMessage(fmt.Sprintf("Could not save: %s", err))
+		return
+	}
+	messages.AddMessage(fmt.Sprintf("Saved to %s", path))
+}
+
+// LoadGame replaces the current game with the one saved at path. The current
+// 	game is left alone if the load fails
+func LoadGame(path string) {
+	loaded, err := sim.LoadFile(path)
+	if err != nil {
+		log.Println(err)
+		messages.AddMessage(fmt.Sprintf("Could not load: %s", err))
+		return
+	}
+
+	DefocusSettlement()
+	ClearDemolishUi()
+	endScreen = nil
+	state = loaded
+	world = CreateWorldView(state.World)
+	RefreshResearchUi()
+	RefreshBuildingsUi()
+	messages.AddMessage(fmt.Sprintf("Loaded %s", path))
+}
+
 // UpdateInputs calls appropriate functions when inputs detected
 func UpdateInputs() {
 
@@ -539,16 +507,27 @@ func UpdateInputs() {
 
 		// TODO some kind of mode, i.e settlement management mode, rather than "UI focused".
 		// 	need some decoupling here
-		if settlementUi.focused && validMouseSelection && settlementUi.selectedCtz != nil && world.squares[mtx][mty].highlighted {
-			// TODO if can assign to
-			settlementUi.selectedCtz.AssignTo(&image.Point{X: mtx, Y: mty})
-		} else if validMouseSelection && world.squares[mtx][mty].kind == TGrass {
+		if MouseInRegionUi() {
+			HandleRegionClick()
+		} else if settlementUi.focused && validMouseSelection && settlementUi.selectedCtz != nil && world.squares[mtx][mty].highlighted {
+			s := world.squares[settlementUi.sx][settlementUi.sy].Settlement
+			job, err := state.JobAt(s, image.Point{X: mtx, Y: mty})
+			// moving can take the citizen out of the settlement, so keep the name
+			name := settlementUi.selectedCtz.Name
+			if err == nil {
+				err = settlementUi.selectedCtz.AssignTo(state, s, job)
+			}
+			if err != nil {
+				messages.AddMessage(fmt.Sprintf("Can't assign %s: %s", name, err))
+			} else {
+				RefreshSettlementUi()
+			}
+		} else if validMouseSelection && world.squares[mtx][mty].Kind == sim.TGrass {
 
 			clickedSquare := world.squares[mtx][mty]
 
 			if clickedSquare.IsEmpty() {
-				// TODO instead spawn the buildings UI
-				world.squares[mtx][mty].settlement = world.CreateSettlement(settlementKinds["VILLAGE"], mtx, mty)
+				PlaceBuilding(mtx, mty)
 			} else if !clickedSquare.HasCompletedSettlement() {
 				DefocusSettlement() // TODO change to defocus selection? idk
 			} else {
@@ -565,21 +544,8 @@ func UpdateInputs() {
 		UpdateSettlementUi()
 	}
 
-	// left in for Kailynn's house
-	if inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonRight) {
-
-		if validMouseSelection && world.squares[mtx][mty].kind == TGrass {
-
-			// TODO should mtx, mty still be global?
-			if world.squares[mtx][mty].settlement == nil {
-				world.squares[mtx][mty].settlement = world.CreateSettlement(settlementKinds["SUBURB"], mtx, mty)
-			}
-		}
-	}
-
-	if inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonMiddle) {
-		// TODO destroy settlement. hopefully go gc is good
-		world.squares[mtx][mty].settlement = nil
+	if inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonMiddle) && validMouseSelection {
+		CreateDemolishUi(mtx, mty)
 	}
 
 	// move cursor north
@@ -587,8 +553,21 @@ func UpdateInputs() {
 		DefocusSettlement()
 	}
 
+	// look inside the focused settlement's square
+	if inpututil.IsKeyJustPressed(ebiten.KeyZ) {
+		fmt.Println(ToggleZoom())
+	}
+
 	// update keyboard cursor position
 	WASD()
+	PanCamera()
+
+	if inpututil.IsKeyJustPressed(ebiten.KeyF5) {
+		SaveGame(QuickSavePath)
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyF9) {
+		LoadGame(QuickSavePath)
+	}
 
 	// TODO ignore tile hover/click if blocked by a UI
 
@@ -618,7 +597,7 @@ func UpdateInputs() {
 func HandleButtonClicks() {
 	// could actually store hovered button as a variable, but this will do for now
 	for _, b := range AllButtons {
-		if b.hover {
+		if b.hover && !b.disabled {
 			if b.executable {
 				fmt.Println(b.exec())
 			}
@@ -632,43 +611,17 @@ func HandleTurnEnd() {
 		return
 	}
 
-	year++
-
-	// every ten years for now
-	if year%10 == 0 && epoch+1 < len(Epochs) {
-		epoch++
-		messages.AddMessage(fmt.Sprintf("You advanced to the %s", Epochs[epoch]))
+	// all of the rules live in sim. we just present what happened
+	report := state.AdvanceTurn()
+	for _, m := range report.Messages {
+		messages.AddMessage(m)
 	}
 
-	// iterate through constructions
-	for i := 0; i < len(world.settlements); i++ {
-		s := world.settlements[i]
-
-		effort := 0.0
-		for i := 0; i < len(s.citizens); i++ {
-			if !s.citizens[i].Assigned() {
-				// if citizens aren't assigned, use their unused effort on
-				// 	eligible constructions
-				effort += s.citizens[i].CalculateEffort()
-			} else {
-				s.citizens[i].Work()
-			}
-		}
-
-		// TODO priority building
-		fmt.Println(fmt.Sprintf("Spare effort: %f", effort))
-		settlements := world.GetAdjacentUncompletedSettlements(s.worldX, s.worldY)
-		count := len(settlements)
-
-		dividedEffort := 0.0
-		if count > 0 {
-			dividedEffort = effort / float64(count)
-		}
-
-		for _, s := range settlements {
-			s.ApplyEffort(dividedEffort)
-		}
-	}
+	// citizens may have aged or died, so the buttons are out of date
+	RefreshSettlementUi()
+	RefreshResearchUi()
+	RefreshBuildingsUi()
+	ClearDemolishUi()
 }
 
 func (g *Game) Update() error {
@@ -696,14 +649,21 @@ func (g *Game) Update() error {
 	// this also finds which tile the mouse is on
 	mtx, mty = UpdateDrawLocations()
 
-	validMouseSelection = CanInteractWithTile(mtx, mty)
-	if TileIsInRange(mtx, mty) {
+	validMouseSelection = state.World.CanInteractWithTile(mtx, mty)
+	if state.World.TileIsInRange(mtx, mty) {
 		world.squares[mtx][mty].input.hovered = true
 	}
 
+	// only quitting and loading make sense once the game is over
+	if state.Over() {
+		UpdateEndScreen()
+		return nil
+	}
+
 	UpdateInputs()
 
 	UpdateSettlementUi()
+	UpdateLaunchButton()
 
 	// we definitely shouldn't accept any user input after this until the next loop
 	HandleTurnEnd()
@@ -716,8 +676,9 @@ func (g *Game) Update() error {
 		MonitorMemory()
 
 		// then resume with game stuff
-		settlementKinds["VILLAGE"].animation.Animate()
-		settlementKinds["SUBURB"].animation.Animate()
+		for _, a := range settlementAnimations {
+			a.Animate()
+		}
 	}
 	// possible to use a float here for proper delta time?
 	ticks++
@@ -757,11 +718,11 @@ func DrawTile(colour *ebiten.ColorM, layer *ebiten.Image, world *World, ttype st
 
 		geomWest := &ebiten.GeoM{}
 		// order is important. scale _then_ translate
-		geomWest.Scale(1, float64(square.height+extraTileHeight))
+		geomWest.Scale(1, float64(square.Height+extraTileHeight))
 		geomWest.Translate(tile.tx, tile.ty+16) // magic number
 
 		geomSouth := &ebiten.GeoM{}
-		geomSouth.Scale(1, float64(square.height+extraTileHeight)) // TODO something else so it goes _below_ the neighbouring tiles if applicable
+		geomSouth.Scale(1, float64(square.Height+extraTileHeight)) // TODO something else so it goes _below_ the neighbouring tiles if applicable
 		geomSouth.Translate(tile.tx+30, tile.ty+16)
 
 		opsFlat := &ebiten.DrawImageOptions{
@@ -787,7 +748,7 @@ func DrawTile(colour *ebiten.ColorM, layer *ebiten.Image, world *World, ttype st
 		tile.opsSouth.ColorM.Reset()
 	}
 
-	if y == 0 || (world.squares[x][y-1].height < world.squares[x][y].height) {
+	if y == 0 || (world.squares[x][y-1].Height < world.squares[x][y].Height) {
 		if ttype != "water" {
 			layer.DrawImage(tileSprites[ttype].westMid, tile.opsWest)
 		}
@@ -795,7 +756,7 @@ func DrawTile(colour *ebiten.ColorM, layer *ebiten.Image, world *World, ttype st
 	}
 
 	// if the south adjacent tile is lower, draw the south side
-	if x < len(world.squares) || (world.squares[x+1][y].height < world.squares[x][y].height) {
+	if x == len(world.squares)-1 || (world.squares[x+1][y].Height < world.squares[x][y].Height) {
 		if ttype != "water" {
 			layer.DrawImage(tileSprites[ttype].southMid, tile.opsSouth)
 		}
@@ -804,8 +765,18 @@ func DrawTile(colour *ebiten.ColorM, layer *ebiten.Image, world *World, ttype st
 
 	layer.DrawImage(tileSprites[ttype].flat, tile.opsFlat)
 
-	if square.resource != nil {
-		layer.DrawImage(square.resource.animation.sprites[0], tile.opsFlat)
+	if square.Resource != nil {
+		if animation, ok := resourceAnimations[square.Resource.ID]; ok {
+			layer.DrawImage(animation.sprites[0], tile.opsFlat)
+		} else {
+			// no sprite yet, so show what it produces in the middle of the tile
+			ops := &ebiten.DrawImageOptions{
+				GeoM:   Copy(&tile.opsFlat.GeoM),
+				ColorM: tile.opsFlat.ColorM,
+			}
+			ops.GeoM.Translate(23, 8)
+			layer.DrawImage(icons[sim.Resources[square.Resource.Produces].Icon], ops)
+		}
 	}
 	// TODO add TGrass property to tile. should be able to loop through tile types
 }
@@ -830,7 +801,7 @@ func DrawWorld(layer *ebiten.Image, world *World) {
 				colour := &ebiten.ColorM{}
 
 				// tile type specific shading
-				if square.kind == TWater {
+				if square.Kind == sim.TWater {
 
 					ttype = "water"
 
@@ -838,7 +809,7 @@ func DrawWorld(layer *ebiten.Image, world *World) {
 						colour.Scale(0.6, 1, 0.6, 1)
 					}
 
-				} else if square.kind == TGrass {
+				} else if square.Kind == sim.TGrass {
 
 					ttype = "grass"
 
@@ -874,7 +845,7 @@ func DrawThings(layer *ebiten.Image) {
 
 	for x := 0; x < len(world.squares); x++ {
 		for y := 0; y < len(world.squares[x]); y++ {
-			if world.squares[x][y].settlement != nil {
+			if world.squares[x][y].Settlement != nil {
 				s := world.squares[x][y]
 				// constructions in progress will be transparent, with their opacity increasing as they near construction
 
@@ -886,9 +857,10 @@ func DrawThings(layer *ebiten.Image) {
 				if !s.HasCompletedSettlement() {
 					ops.ColorM.Scale(1, 1, 1, 0.4)
 					// do not animate things under construction as it more clearly indicates that it's not in operation
-					frame = s.settlement.kind.animation.sprites[0]
+					frame = settlementAnimations[s.Settlement.Kind.ID].sprites[0]
 				} else {
-					frame = s.settlement.kind.animation.sprites[world.squares[x][y].settlement.kind.animation.frame]
+					animation := settlementAnimations[s.Settlement.Kind.ID]
+					frame = animation.sprites[animation.frame]
 				}
 
 				layer.DrawImage(frame, ops)
@@ -902,6 +874,9 @@ func DrawHighlightLayer(layer *ebiten.Image) {
 
 	layer.Clear()
 
+	// only worked out if a construction is highlighted
+	var forecast map[*sim.Settlement]float64
+
 	// TODO redraw property
 	for x := 0; x < len(world.squares); x++ {
 		for y := len(world.squares[x]) - 1; y > -1; y-- {
@@ -927,9 +902,11 @@ func DrawHighlightLayer(layer *ebiten.Image) {
 				square := world.squares[x][y]
 				if square.HasResource() {
 
-				} else if !square.HasCompletedSettlement() {
-					words := fmt.Sprintf("%.1f %%", square.settlement.progress*100)
-					// TODO show progress on next turn
+				} else if square.Settlement != nil && !square.HasCompletedSettlement() {
+					if forecast == nil {
+						forecast = state.ConstructionForecast()
+					}
+					words := ConstructionLabel(square.Settlement, forecast)
 					width := text.BoundString(fontSmall, words).Dx()
 					text.Draw(layer, words, fontSmall, int(tile.tx)+32-(width/2), int(tile.ty)+16, color.White)
 				}
@@ -953,44 +930,6 @@ func DrawLayers(screen *ebiten.Image) {
 	screen.DrawImage(uiLayer, &ebiten.DrawImageOptions{})
 }
 
-func (c *Citizen) ToString() string {
-	return fmt.Sprintf("%s, %s, %d, no job", c.name, c.gender, c.age)
-}
-
-func (c *Citizen) ToTerseString() string {
-	// %s for strings, %c for chars
-	return fmt.Sprintf("Citizen, %c%d", strings.ToUpper(c.gender)[0], c.age)
-}
-
-func (c *Citizen) Assigned() bool {
-	return c.assignment != nil
-}
-
-func (c *Citizen) AssignTo(location *image.Point) {
-	// TODO validate assignment
-	c.assignment = location
-	resource := world.squares[location.X][location.Y].resource
-	fmt.Println(fmt.Sprintf("Assigned citizen %s to %s", settlementUi.selectedCtz.name, resource.name))
-}
-
-func (c *Citizen) Work() {
-	resource := world.squares[c.assignment.X][c.assignment.Y].resource
-	// TODO morale bonus?
-	if resource.name == "wood cutting" {
-		stocks.wood += c.CalculateEffort()
-	}
-
-	c.proficiencies[resource.name] += 0.1
-	fmt.Println(fmt.Sprintf("%s's %s proficiency increased to %d", c.name, resource.name, c.proficiencies[resource.name]))
-}
-
-// TODO task type
-// 	or resource type?
-func (c *Citizen) CalculateEffort() float64 {
-	// TODO get citizen proficiency
-	return 0.1
-}
-
 // TODO button state variable
 // CreateButton appends it to the global buttons list, returns the button and the text width (TODO maybe make this whole button width?)
 func CreateButton(img *UiSprite, str string, x, y int) (*Button, int) {
@@ -1101,7 +1040,8 @@ func (b *Button) DrawButton(layer *ebiten.Image) {
 
 		ops = &ebiten.DrawImageOptions{}
 		if b.disabled {
-			ops.ColorM.Scale(0.8, 0.8, 0.8, 1)
+			// greyed out
+			ops.ColorM.Scale(0.5, 0.5, 0.5, 1)
 		}
 		b.cachedImg.DrawImage(textImg, ops)
 		b.redraw = false
@@ -1139,17 +1079,28 @@ func DrawUi(layer *ebiten.Image) {
 	layer.Clear()
 
 	// the font should totally upgrade with each age
-	text.Draw(layer, Epochs[epoch], fontTitle, 8, 16, color.White)
+	text.Draw(layer, sim.Epochs[state.Epoch], fontTitle, 8, 16, color.White)
 
 	// smaller font for more detailed information
 	// TODO cache this value in update
 	// TODO previous frame state (so we can avoid unnecessary calculations)
 	civs := 0
-	for i := 0; i < len(world.settlements); i++ {
-		civs += len(world.settlements[i].citizens)
+	for i := 0; i < len(state.World.Settlements); i++ {
+		civs += len(state.World.Settlements[i].Citizens)
 	}
 	text.Draw(layer, fmt.Sprintf("Citizens: %d", civs), fontDetail, 8, 30, color.White)
-	text.Draw(layer, fmt.Sprintf("Year: %d", year), fontDetail, 8, 44, color.White)
+	text.Draw(layer, fmt.Sprintf("Year: %d", state.Year), fontDetail, 8, 44, color.White)
+	text.Draw(layer, fmt.Sprintf("Seed: %d", state.Rng.Seed()), fontDetail, 8, 58, color.White)
+	text.Draw(layer, ResearchStatus(), fontDetail, 8, 72, color.White)
+	text.Draw(layer, NextEpochStatus(), fontDetail, 8, 86, color.White)
+	if state.Epoch >= sim.AnnihilationEpoch {
+		risk := state.AnnihilationRisk()
+		colour := color.Color(color.White)
+		if risk > 0 {
+			colour = color.RGBA{R: 255, G: 96, B: 96, A: 255}
+		}
+		text.Draw(layer, fmt.Sprintf("Annihilation risk: %.1f%%", risk*100), fontDetail, 8, 100, colour)
+	}
 
 	for _, v := range SButtons {
 		v.DrawButton(layer)
@@ -1160,12 +1111,44 @@ func DrawUi(layer *ebiten.Image) {
 	// draw icons
 	textY := 12
 	resourceUi := ebiten.NewImage(200, 200)
-	ops := &ebiten.DrawImageOptions{}
+	production := state.Production()
 
-	resourceUi.DrawImage(icons[IconsWood], ops)
-	text.Draw(resourceUi, fmt.Sprintf("%f", stocks.wood), fontDetail, 20, textY, color.White)
+	for i, id := range sim.ResourceOrder {
+		resource := sim.Resources[id]
 
-	ops = &ebiten.DrawImageOptions{}
+		ops := &ebiten.DrawImageOptions{}
+		ops.GeoM.Translate(0, float64(i*18))
+		resourceUi.DrawImage(icons[resource.Icon], ops)
+
+		// show what's coming in and, for food and medicine, what's being
+		// 	used up each year
+		words := fmt.Sprintf("%.1f (+%.1f)", state.Stocks[id], production[id])
+		colour := color.Color(color.White)
+		consumption := 0.0
+		switch id {
+		case sim.ResFood:
+			consumption = state.FoodConsumption()
+		case sim.ResMedicine:
+			consumption = state.MedicineDemand()
+		}
+		if consumption > 0 {
+			words = fmt.Sprintf("%.1f (+%.1f -%.1f)", state.Stocks[id], production[id], consumption)
+			if production[id] < consumption {
+				colour = color.RGBA{R: 255, G: 96, B: 96, A: 255}
+			}
+		}
+		text.Draw(resourceUi, words, fontDetail, 20, textY+(i*18), colour)
+	}
+
+	// and how many have somewhere to live
+	housed, capacity := state.Housing()
+	words := fmt.Sprintf("Housed %d/%d", housed, capacity)
+	if homeless := state.Homeless(); homeless > 0 {
+		words += fmt.Sprintf(", %d homeless", homeless)
+	}
+	text.Draw(resourceUi, words, fontDetail, 0, textY+(len(sim.ResourceOrder)*18), color.White)
+
+	ops := &ebiten.DrawImageOptions{}
 	ops.GeoM.Translate(400, 200)
 	layer.DrawImage(resourceUi, ops)
 
@@ -1173,74 +1156,48 @@ func DrawUi(layer *ebiten.Image) {
 
 func HighlightAvailableTiles(x, y int, highlighted bool) {
 
-	// TODO don't forget array bounds
-	// TODO use a different or simpler type
-	works := make(map[string]*Work)
-	works["here"] = &Work{x: x, y: y}
-	works["north"] = &Work{x: x - 1, y: y}
-	works["east"] = &Work{x: x, y: y + 1}
-	works["south"] = &Work{x: x + 1, y: y}
-	works["west"] = &Work{x: x, y: y - 1}
-
 	world.squares[x][y].input.selected = highlighted
-	for _, v := range works {
-		square := &world.squares[v.x][v.y]
 
-		if square.IsEmpty() {
-			continue
-		} else {
-			square.highlighted = highlighted
+	// clear everything first, as the jobs may have changed since the tiles
+	// 	were highlighted and with transit they can be anywhere on the map
+	for ix := range world.squares {
+		for iy := range world.squares[ix] {
+			world.squares[ix][iy].highlighted = false
 		}
 	}
-}
-
-func GetAvailableJobs(x, y int) []*Job {
-
-	// TODO don't forget array bounds
-	works := make(map[string]*Work)
-	works["here"] = &Work{x: x, y: y}
-	works["north"] = &Work{x: x - 1, y: y}
-	works["east"] = &Work{x: x, y: y + 1}
-	works["south"] = &Work{x: x + 1, y: y}
-	works["west"] = &Work{x: x, y: y - 1}
 
-	// TODO jobs type and jobs list (assigned, etc)
-	jobs := []*Job{}
-	here := world.squares[x][y]
-
-	// if unfinished settlement, no jobs should be available
-	if !here.HasCompletedSettlement() {
-		return jobs
+	s := world.squares[x][y].Settlement
+	if !highlighted || s == nil {
+		return
 	}
 
-	// TODO tool tip of job info
-	// TODO warn on excess effort
-	for k, v := range works {
-
-		square := world.squares[v.x][v.y]
-
-		if square.IsEmpty() {
-			continue
-		}
-
-		if square.HasCompletedSettlement() {
+	for _, job := range state.AvailableJobs(s) {
+		world.squares[job.Target.X][job.Target.Y].highlighted = true
+	}
+}
 
-			// don't show move for "here"
-			if v.x != x || v.y != y {
-				jobs = append(jobs, &Job{
-					kind: fmt.Sprintf("move %s", k),
-					work: v,
-				})
-			}
-		} else if v.x != x || v.y != y {
-			jobs = append(jobs, &Job{
-				kind: fmt.Sprintf("build %s", k),
-				work: v,
-			})
-		}
+// Direction names where to is from from, the way the settlement window
+// 	describes jobs
+func Direction(from, to image.Point) string {
+	switch to.Sub(from) {
+	case image.Point{}:
+		return "here"
+	case image.Point{X: -1}:
+		return "north"
+	case image.Point{Y: 1}:
+		return "east"
+	case image.Point{X: 1}:
+		return "south"
+	case image.Point{Y: -1}:
+		return "west"
 	}
+	return fmt.Sprintf("%d,%d", to.X, to.Y)
+}
 
-	return jobs
+// JobLabel describes a job for a citizen of the settlement at x,y, with how
+// 	many places are taken
+func JobLabel(job sim.Job, x, y int) string {
+	return fmt.Sprintf("%s %s (%d/%d)", job.Kind, Direction(image.Point{X: x, Y: y}, job.Target), state.JobTaken(job), state.JobCapacity(job))
 }
 
 func (ui *SettlementUi) SelectCitizen(idx int) {
@@ -1256,6 +1213,31 @@ func (ui *SettlementUi) SelectCitizen(idx int) {
 	}
 
 	settlementUi.DisableJobSelection(!ui.selectCtzButtons[idx].selected)
+	settlementUi.selectedID = 0
+	if settlementUi.selectedCtz != nil {
+		settlementUi.selectedID = settlementUi.selectedCtz.ID
+	}
+	ui.UpdateJobLabels()
+	// the region shows where the selected citizen can work
+	regionUi.window.redraw = true
+}
+
+// UpdateJobLabels shows how much effort the selected citizen would put into
+// 	each job, so the benefit of specialising is visible
+func (ui *SettlementUi) UpdateJobLabels() {
+	for i, b := range ui.selectJobButtons {
+		job := sim.Job{Kind: sim.JobIdle}
+		label := string(sim.JobIdle)
+		if i < len(ui.jobs) {
+			job = ui.jobs[i]
+			label = JobLabel(job, ui.sx, ui.sy)
+		}
+		if ui.selectedCtz != nil {
+			label += fmt.Sprintf(" %.2f", state.JobEffort(ui.selectedCtz, job))
+		}
+		b.content = label
+		b.SetRedraw()
+	}
 }
 
 func (ui *SettlementUi) DisableJobSelection(disabled bool) {
@@ -1272,22 +1254,68 @@ func ClearSettlementUi() {
 	settlementUi.selectCtzButtons = []*Button{}
 	settlementUi.selectJobButtons = []*Button{}
 	settlementUi.window.Destroy()
+	ClearConstructionUi()
 	fmt.Println(fmt.Sprintf("Defocused"))
 }
 
+// RefreshSettlementUi recreates the settlement UI, if it's open, to reflect
+// 	changes to the settlement
+func RefreshSettlementUi() {
+	if settlementUi.focused {
+		// what's next door may have been built, demolished or cancelled
+		HighlightAvailableTiles(settlementUi.sx, settlementUi.sy, true)
+		ClearSettlementUi()
+		CreateSettlementUi()
+	}
+	regionUi.window.redraw = true
+}
+
+// AddIneligibleReason notes why a citizen can't work, once per reason
+func (ui *SettlementUi) AddIneligibleReason(reason string) {
+	for _, r := range ui.ineligibleReasons {
+		if r == reason {
+			return
+		}
+	}
+	ui.ineligibleReasons = append(ui.ineligibleReasons, reason)
+}
+
 func CreateSettlementUi() {
 
 	settlementUi.focused = true
 	settlementUi.redraw = true
 	settlementUi.selectCtzButtons = []*Button{}
 	settlementUi.selectJobButtons = []*Button{}
+	settlementUi.ineligibleReasons = []string{}
 
 	// citizens
 	square := world.squares[settlementUi.sx][settlementUi.sy]
-	for i := 0; i < len(square.settlement.citizens); i++ {
-		citizenText := square.settlement.citizens[i].ToTerseString()
+	selected := -1
+	for i := 0; i < len(square.Settlement.Citizens); i++ {
+		c := &square.Settlement.Citizens[i]
+		citizenText := c.ToTerseString()
+		if c.Assigned() {
+			citizenText += fmt.Sprintf(" (%s)", c.JobKind())
+		}
+		if c.Sick > 0 {
+			citizenText += " sick"
+		}
+		if c.Homeless {
+			citizenText += " homeless"
+		}
+
+		// citizens who can't work are greyed out and marked, with the
+		// 	reason shown underneath
+		eligibility := c.CanWork(state.Epoch)
+		if eligibility != nil {
+			citizenText += " *"
+			settlementUi.AddIneligibleReason(eligibility.Error())
+		} else if c.ID == settlementUi.selectedID {
+			selected = i
+		}
 
 		b, _ := CreateButton(&btn, citizenText, 0, 0)
+		b.disabled = eligibility != nil
 		b.executable = true
 		idx := i
 		b.exec = func() string {
@@ -1295,7 +1323,7 @@ func CreateSettlementUi() {
 				// we're going to flip this in SelectCitizen. this is a bit shit
 				settlementUi.selectedCtz = nil
 			} else {
-				settlementUi.selectedCtz = &square.settlement.citizens[idx]
+				settlementUi.selectedCtz = &square.Settlement.Citizens[idx]
 			}
 			settlementUi.SelectCitizen(idx)
 			return "Selected citizen"
@@ -1304,27 +1332,57 @@ func CreateSettlementUi() {
 		settlementUi.selectCtzButtons = append(settlementUi.selectCtzButtons, b)
 	}
 
-	// jobs
-	jobs := GetAvailableJobs(settlementUi.sx, settlementUi.sy)
+	// jobs, then a way to stop doing them
+	jobs := state.AvailableJobs(square.Settlement)
+	labels := []string{}
+	for _, job := range jobs {
+		labels = append(labels, JobLabel(job, settlementUi.sx, settlementUi.sy))
+	}
+	labels = append(labels, string(sim.JobIdle))
 
-	for j := 0; j < len(jobs); j++ {
-		jobsText := jobs[j].kind
+	for j := 0; j < len(labels); j++ {
+		jobsText := labels[j]
+		idx := j
 		// TODO make this a function of Window?
 		b, _ := CreateButton(&btn, jobsText, 0, 0)
 		b.executable = true
 		b.disabled = true
 		b.exec = func() string {
-			if settlementUi.selectedCtz == nil {
+			c := settlementUi.selectedCtz
+			if c == nil {
 				return "No citizen selected"
 			}
-			return fmt.Sprintf("Assigned job '%s' to %s", jobsText, settlementUi.selectedCtz.name)
+			name := c.Name
+			if idx == len(jobs) {
+				c.Unassign()
+			} else if err := c.AssignTo(state, square.Settlement, jobs[idx]); err != nil {
+				messages.AddMessage(fmt.Sprintf("Can't assign %s: %s", name, err))
+				return err.Error()
+			}
+			RefreshSettlementUi()
+			return fmt.Sprintf("Assigned job '%s' to %s", jobsText, name)
 		}
 		b.SetWindow(settlementUi.window)
 		settlementUi.selectJobButtons = append(settlementUi.selectJobButtons, b)
 	}
 
-	// might not be necessary to store this
 	settlementUi.jobs = jobs
+
+	settlementUi.travellers = []string{}
+	for _, c := range state.Travellers(square.Settlement) {
+		settlementUi.travellers = append(settlementUi.travellers, c.ToTerseString())
+	}
+
+	// keep the same citizen selected when the window is recreated
+	settlementUi.selectedCtz = nil
+	if selected >= 0 {
+		settlementUi.selectedCtz = &square.Settlement.Citizens[selected]
+		settlementUi.selectCtzButtons[selected].selected = true
+		settlementUi.DisableJobSelection(false)
+	}
+	settlementUi.UpdateJobLabels()
+
+	CreateConstructionUi()
 }
 
 func UpdateSettlementUi() {
@@ -1350,9 +1408,16 @@ func DrawSettlementUi(screen *ebiten.Image) {
 		x := 4
 		y := 40
 
-		citizensText := fmt.Sprintf("Citizens (%d)", len(square.settlement.citizens))
+		citizensText := fmt.Sprintf("Citizens, housed %d/%d", square.Settlement.Housed(), square.Settlement.Kind.Popcap)
+		if homeless := square.Settlement.Homeless(); homeless > 0 {
+			citizensText += fmt.Sprintf(", %d homeless", homeless)
+		}
 		text.Draw(canvas, citizensText, fontDetail, x, y, color.White)
 
+		y += 10
+		healthText := fmt.Sprintf("Education %.1f, healthcare %.0f%%, %d sick", square.Settlement.AverageEducation(), state.Healthcare(square.Settlement)*100, square.Settlement.SickCount())
+		text.Draw(canvas, healthText, fontDetail, x, y, color.White)
+
 		y += 10
 		if len(settlementUi.selectCtzButtons) == 0 {
 
@@ -1363,14 +1428,29 @@ func DrawSettlementUi(screen *ebiten.Image) {
 				b.DrawButtonAt(canvas, x, y)
 				y += 20
 			}
+			for _, r := range settlementUi.ineligibleReasons {
+				y += 4
+				text.Draw(canvas, fmt.Sprintf("* %s", r), fontSmall, x, y, color.Gray{Y: 160})
+				y += 6
+			}
+		}
+
+		if len(settlementUi.travellers) > 0 {
+			y += 10
+			text.Draw(canvas, fmt.Sprintf("Arriving next year (%d)", len(settlementUi.travellers)), fontDetail, x, y, color.White)
+			for _, t := range settlementUi.travellers {
+				y += 12
+				text.Draw(canvas, t, fontSmall, x, y, color.Gray{Y: 160})
+			}
 		}
 
 		// no use for the BALLS button right now
 		b, _ := CreateButton(&btn, "BALLS BALLS BALLS", x, height-20)
 		b.DrawButton(canvas)
+		text.Draw(canvas, "Z: region", fontSmall, width-50, height-8, color.Gray{Y: 160})
 
 		// draw jobs UI
-		x = 100
+		x = 140
 		y = 40
 
 		text.Draw(canvas, "Jobs", fontDetail, x, y, color.White)
@@ -1417,14 +1497,20 @@ func (g *Game) Draw(screen *ebiten.Image) {
 	DrawHighlightLayer(highlightLayer)
 	DrawUi(uiLayer)
 	DrawSettlementUi(uiLayer)
+	DrawConstructionUi(uiLayer)
+	DrawResearchUi(uiLayer)
+	DrawBuildingsUi(uiLayer)
+	DrawRegionUi(uiLayer)
+	DrawDemolishUi(uiLayer)
 	DrawLayers(screen)
+	DrawEndScreen(screen)
 
 	// TODO don't calculate mouse pos on the draw call. this is for debugging only
 	mx, my := ebiten.CursorPosition()
 	if debug {
-		ebitenutil.DebugPrintAt(screen, fmt.Sprintf("Mouse pos: %d,%d", mx, my), 16, 60)
-		ebitenutil.DebugPrintAt(screen, fmt.Sprintf("Mouse on tile: %d, %d", mtx, mty), 16, 80)
-		ebitenutil.DebugPrintAt(screen, fmt.Sprintf("Current FPS: %f", ebiten.CurrentFPS()), 16, 100)
+		ebitenutil.DebugPrintAt(screen, fmt.Sprintf("Mouse pos: %d,%d", mx, my), 16, 116)
+		ebitenutil.DebugPrintAt(screen, fmt.Sprintf("Mouse on tile: %d, %d", mtx, mty), 16, 136)
+		ebitenutil.DebugPrintAt(screen, fmt.Sprintf("Current FPS: %f", ebiten.CurrentFPS()), 16, 156)
 	}
 }
 
@@ -1441,181 +1527,32 @@ func (g *Game) Layout(outsideWidth, outsideHeight int) (screenWidth, screenHeigh
 	return sWidth, sHeight
 }
 
-func CreateTile(kind int, height int, liquid bool) Square {
-	return Square{
-		kind:        kind,
-		moved:       true,
-		height:      height,
-		liquid:      liquid,
-		highlighted: false,
-		tile:        &Tile{},
-		input: &Input{
-			selected: false,
-			hovered:  false,
-		},
-		// Texture: ,
-	}
-}
-
-func CreateResearch() Research {
-
-	return Research{
-		Husbandry: false,
-		Transit:   false,
-	}
-}
-
-func CreateSquare() Square {
-	return Square{
-		moved:       true,
-		highlighted: false,
-		tile:        &Tile{},
-		input: &Input{
-			selected: false,
-			hovered:  false,
-		},
+// CreateWorldView wraps each square of the sim world with the state needed
+// 	to draw it. Must be called again if the sim world is replaced
+func CreateWorldView(w *sim.World) World {
+
+	squares := make([][]Square, len(w.Squares))
+	for x := 0; x < len(w.Squares); x++ {
+		squares[x] = make([]Square, len(w.Squares[x]))
+		for y := 0; y < len(w.Squares[x]); y++ {
+			squares[x][y] = Square{
+				Square:      &w.Squares[x][y],
+				moved:       true,
+				highlighted: false,
+				tile:        &Tile{},
+				input: &Input{
+					selected: false,
+					hovered:  false,
+				},
+			}
+		}
 	}
-}
-
-func CreateWorld() World {
 
-	w := World{
-		squares:   IslandWorldTiles(),
+	return World{
+		squares:   squares,
 		redraw:    true,
 		cachedImg: nil,
 	}
-
-	w.CreateSettlements()
-
-	return w
-}
-
-// CreateSettlement creates a settlement add it to the world's settlement
-// 	list (if it's not nothing) and return the settlement so it can be
-// 	added to the world grid location by the calling code
-func (w *World) CreateSettlement(kind *SettlementKind, worldX, worldY int) *Settlement {
-
-	s := &Settlement{
-		worldX:    worldX,
-		worldY:    worldY,
-		kind:      kind,
-		completed: false,
-		progress:  0,
-		citizens:  []Citizen{},
-	}
-
-	// TODO remove all concept of a nothing settlement. just use nil check
-	// if !kind.nothing {
-	// w.settlementList = append(w.settlementList, s)
-	// }
-
-	return s
-}
-
-func CreateProficiencies() map[string]float64 {
-
-	p := make(map[string]float64)
-	for _, v := range resourcesTypes {
-		p[v.name] = 0.0
-	}
-	return p
-}
-
-func CreateSpawnSettlement(worldX, worldY int) *Settlement {
-
-	sk := settlementKinds["VILLAGE"]
-	c := []Citizen{}
-
-	for i := 0; i < sk.popcap/2; i++ {
-
-		var gender string
-		var name string
-
-		if i%2 == 0 {
-			gender = "female"
-			// right exclusive, neat
-			name = FirstNamesFemale[rand.Intn(len(FirstNamesFemale))]
-		} else {
-			gender = "male"
-			name = FirstNamesMale[rand.Intn(len(FirstNamesMale))]
-		}
-
-		c = append(c, Citizen{
-			name:          name,
-			gender:        gender,
-			genetics:      100,
-			age:           18,
-			proficiencies: CreateProficiencies(),
-		})
-	}
-
-	return &Settlement{
-		worldX:    worldX,
-		worldY:    worldY,
-		kind:      sk,
-		completed: true,
-		citizens:  c,
-	}
-}
-
-func (s *Settlement) ApplyEffort(effort float64) {
-
-	// TODO error if already completed
-	if !s.completed {
-		s.progress += effort
-		if s.progress >= 1 {
-			s.completed = true
-			messages.AddMessage(fmt.Sprintf("Construction completed on '%s'", s.kind.name))
-		}
-	}
-}
-
-func (w *World) CreateSettlements() {
-
-	list := []*Settlement{}
-
-	// spawn village in the middle(ish) of the map
-	s := CreateSpawnSettlement(3, 4)
-	list = append(list, s)
-
-	w.squares[3][4].settlement = s
-	w.settlements = list
-}
-
-func (w *World) GetAdjacentSettlements(x, y int) []*Settlement {
-
-	settlements := []*Settlement{}
-
-	if x > 0 && world.squares[x-1][y].settlement != nil {
-		settlements = append(settlements, world.squares[x-1][y].settlement)
-	}
-
-	if y > 0 && world.squares[x][y-1].settlement != nil {
-		settlements = append(settlements, world.squares[x][y-1].settlement)
-	}
-
-	if x < len(world.squares)-1 && world.squares[x+1][y].settlement != nil {
-		settlements = append(settlements, world.squares[x+1][y].settlement)
-	}
-
-	if x < len(world.squares) && y < len(world.squares[x])-1 && world.squares[x][y+1].settlement != nil {
-		settlements = append(settlements, world.squares[x][y+1].settlement)
-	}
-
-	return settlements
-}
-
-func (w *World) GetAdjacentUncompletedSettlements(x, y int) []*Settlement {
-
-	settlements := []*Settlement{}
-
-	for _, s := range w.GetAdjacentSettlements(x, y) {
-		if !s.completed {
-			settlements = append(settlements, s)
-		}
-	}
-
-	return settlements
 }
 
 func CreateLayers(sWidth, sHeight int) {
@@ -1631,7 +1568,7 @@ func CreateUi() {
 	settlementUi = SettlementUi{
 		// TODO move to bottom right?
 		window: &Window{
-			width:  150,
+			width:  280,
 			height: 200,
 			px:     16,
 			py:     16,
@@ -1643,6 +1580,57 @@ func CreateUi() {
 		focused: false,
 	}
 
+	constructionUi = ConstructionUi{
+		window: &Window{
+			width:  280,
+			height: 140,
+			px:     16,
+			py:     224,
+			redraw: true,
+		},
+	}
+
+	buildingsUi = BuildingsUi{
+		window: &Window{
+			width:  220,
+			height: 480,
+			px:     496,
+			py:     16,
+			redraw: true,
+		},
+		chosen: sim.SkVillage,
+	}
+
+	regionUi = RegionUi{
+		window: &Window{
+			width:  sim.RegionSize*regionCellSize + 8,
+			height: sim.RegionSize*regionCellSize + 70,
+			px:     306,
+			py:     250,
+			redraw: true,
+		},
+	}
+
+	demolishUi = DemolishUi{
+		window: &Window{
+			width:  220,
+			height: 90,
+			px:     400,
+			py:     200,
+			redraw: true,
+		},
+	}
+
+	researchUi = ResearchUi{
+		window: &Window{
+			width:  180,
+			height: 220,
+			px:     306,
+			py:     16,
+			redraw: true,
+		},
+	}
+
 	messages = CreateMessages()
 	SButtons = make(map[string]*Button)
 
@@ -1657,6 +1645,17 @@ func CreateUi() {
 	SButtons[BtnEndTurn], bw = CreateButton(&btn, "End turn", bx, by)
 	bx += bw
 	SButtons[BtnShowBuildings], bw = CreateButton(&btn, "Buildings", bx, by)
+	SButtons[BtnShowBuildings].executable = true
+	SButtons[BtnShowBuildings].exec = ToggleBuildingsUi
+	bx += bw
+	SButtons[BtnResearch], bw = CreateButton(&btn, "Research", bx, by)
+	SButtons[BtnResearch].executable = true
+	SButtons[BtnResearch].exec = ToggleResearchUi
+	bx += bw
+	SButtons[BtnLaunch], bw = CreateButton(&btn, "Launch", bx, by)
+	SButtons[BtnLaunch].executable = true
+	SButtons[BtnLaunch].disabled = true
+	SButtons[BtnLaunch].exec = LaunchSpaceship
 	bx += bw
 	// "anonymous" button
 	CreateButton(&btn, "BALLS BALLS BALLS", bx, by)
@@ -1707,38 +1706,38 @@ func LoadSprites() {
 // because we can't use consts for stuff like this
 func defs() {
 
-	settlementKinds = make(map[string]*SettlementKind)
-	resourcesTypes = make(map[string]*ResourceType)
-
-	settlementKinds["NOTHING"] = &SettlementKind{
-		nothing: true,
-		popcap:  0,
-	}
-
-	settlementKinds["VILLAGE"] = &SettlementKind{
-		name:      "village",
-		animation: LoadAnimatedSprite(filepath.Join("img", "sprites", "buildings"), "village", 2),
-		popcap:    10,
-		nothing:   false,
-		// means it will take two person years to construct
-		effort: 0.5,
-	}
-
-	settlementKinds["SUBURB"] = &SettlementKind{
-		animation: LoadAnimatedSprite(filepath.Join("img", "sprites", "buildings"), "house", 2),
-		popcap:    20,
-		nothing:   false,
-		effort:    0.2,
-	}
-
-	resourcesTypes[rtForest] = &ResourceType{
-		name:      "wood cutting",
-		animation: LoadAnimatedSprite(filepath.Join("img", "sprites", "resources"), "forest", 1),
-	}
-
-	nothing = Settlement{
-		kind: settlementKinds["NOTHING"],
-	}
+	settlementAnimations = make(map[string]*Animation)
+	resourceAnimations = make(map[string]*Animation)
+
+	village := LoadAnimatedSprite(filepath.Join("img", "sprites", "buildings"), "village", 2)
+	settlementAnimations[sim.SkVillage] = &village
+	house := LoadAnimatedSprite(filepath.Join("img", "sprites", "buildings"), "house", 2)
+	settlementAnimations[sim.SkSuburb] = &house
+
+	forest := LoadAnimatedSprite(filepath.Join("img", "sprites", "resources"), "forest", 1)
+	resourceAnimations[sim.RtForest] = &forest
+	fish := LoadAnimatedSprite(filepath.Join("img", "sprites", "resources"), "fish", 1)
+	resourceAnimations[sim.RtFish] = &fish
+	monolith := LoadAnimatedSprite(filepath.Join("img", "sprites", "resources"), "monolith", 1)
+	resourceAnimations[sim.RtMonolith] = &monolith
+	farm := LoadAnimatedSprite(filepath.Join("img", "sprites", "buildings"), "farm", 1)
+	settlementAnimations[sim.SkFarm] = &farm
+	launchPad := LoadAnimatedSprite(filepath.Join("img", "sprites", "buildings"), "launchpad", 1)
+	settlementAnimations[sim.SkLaunchPad] = &launchPad
+	rocket := LoadAnimatedSprite(filepath.Join("img", "sprites", "buildings"), "rocket", 1)
+	settlementAnimations[sim.SkRocket] = &rocket
+	habitat := LoadAnimatedSprite(filepath.Join("img", "sprites", "buildings"), "habitat", 1)
+	settlementAnimations[sim.SkHabitat] = &habitat
+	engine := LoadAnimatedSprite(filepath.Join("img", "sprites", "buildings"), "engine", 1)
+	settlementAnimations[sim.SkEngine] = &engine
+	school := LoadAnimatedSprite(filepath.Join("img", "sprites", "buildings"), "school", 1)
+	settlementAnimations[sim.SkSchool] = &school
+	healersHut := LoadAnimatedSprite(filepath.Join("img", "sprites", "buildings"), "healershut", 1)
+	settlementAnimations[sim.SkHealersHut] = &healersHut
+	infirmary := LoadAnimatedSprite(filepath.Join("img", "sprites", "buildings"), "infirmary", 1)
+	settlementAnimations[sim.SkInfirmary] = &infirmary
+	hospital := LoadAnimatedSprite(filepath.Join("img", "sprites", "buildings"), "hospital", 1)
+	settlementAnimations[sim.SkHospital] = &hospital
 
 	LoadIcons()
 
@@ -1755,21 +1754,54 @@ func Init() {
 	CreateUi()
 
 	// new game
-	stocks = Stocks{
-		wood: 0,
+	var err error
+	state, err = sim.CreateGameState(seed, worldOptions)
+	if err != nil {
+		log.Fatal(err)
 	}
-	world = CreateWorld()
-	research = CreateResearch()
+	world = CreateWorldView(state.World)
 }
 
 func main() {
-	fmt.Println("Starting...")
+
+	worldOptions = sim.DefaultWorldOptions()
+	shape := flag.String("shape", worldOptions.Shape.String(), "world shape: island, continent or archipelago")
+	flag.Int64Var(&seed, "seed", 0, "seed for world generation and events. random if not set")
+	flag.IntVar(&worldOptions.Width, "width", worldOptions.Width, "world width in tiles")
+	flag.IntVar(&worldOptions.Height, "height", worldOptions.Height, "world height in tiles")
+	flag.Float64Var(&worldOptions.WaterLevel, "water", worldOptions.WaterLevel, "fraction of the world that is water, 0-1")
+	flag.Float64Var(&worldOptions.ForestDensity, "forest", worldOptions.ForestDensity, "fraction of land that is forest, 0-1")
+	flag.Float64Var(&worldOptions.FishDensity, "fish", worldOptions.FishDensity, "chance of coastal water having fish, 0-1")
+	flag.IntVar(&worldOptions.Relief, "relief", worldOptions.Relief, "how many steps hills can rise")
+	flag.Parse()
+
+	var err error
+	worldOptions.Shape, err = sim.ParseWorldShape(*shape)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := worldOptions.Validate(); err != nil {
+		log.Fatal(err)
+	}
+
+	seeded := false
+	flag.Visit(func(f *flag.Flag) {
+		if f.Name == "seed" {
+			seeded = true
+		}
+	})
+	if !seeded {
+		seed = time.Now().UnixNano()
+	}
+
+	fmt.Println(fmt.Sprintf("Starting with seed %d...", seed))
 	initialised = false
 	renderTilesLayer = true
 	renderThingsLayer = true
 
 	// do this with a function. it's to make the screen size fit the map
-	//  (assuming 8x8) like minesweeper
+	//  (assuming 8x8) like minesweeper. bigger maps can be panned with the
+	//  arrow keys
 	ebiten.SetWindowSize(WindowWidth, WindowHeight)
 	ebiten.SetWindowTitle("Kingdom")
 
@@ -0,0 +1,205 @@
+package main
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"strings"
+
+	"github.com/hajimehoshi/ebiten"
+	"github.com/hajimehoshi/ebiten/ebitenutil"
+	"github.com/hajimehoshi/ebiten/text"
+	"github.com/jakestanley/golang-civclone/sim"
+)
+
+// regionCellSize is the width and height of a region square in the region
+// 	view, in pixels
+const regionCellSize = 22
+
+// RegionUi is a zoomed in view of the region inside a settlement's square
+type RegionUi struct {
+	window  *Window
+	focused bool
+	// world coordinates of the square being looked inside
+	x, y int
+}
+
+var regionUi RegionUi
+
+var (
+	regionWater  = color.RGBA{R: 40, G: 90, B: 200, A: 255}
+	regionGrass  = color.RGBA{R: 60, G: 150, B: 60, A: 255}
+	regionCentre = color.RGBA{R: 220, G: 180, B: 40, A: 255}
+	// regionAvailable marks squares the selected citizen could work
+	regionAvailable = color.RGBA{R: 255, G: 191, A: 255}
+)
+
+// ZoomIn shows the region inside the focused settlement's square
+func ZoomIn() string {
+	if !settlementUi.focused {
+		return "No settlement to zoom into"
+	}
+	regionUi.focused = true
+	regionUi.x = settlementUi.sx
+	regionUi.y = settlementUi.sy
+	regionUi.window.redraw = true
+	return fmt.Sprintf("Zoomed into %d,%d", regionUi.x, regionUi.y)
+}
+
+// ZoomOut goes back to just the world view
+func ZoomOut() string {
+	regionUi.focused = false
+	return "Zoomed out"
+}
+
+// ToggleZoom zooms into the focused settlement's region, or back out
+func ToggleZoom() string {
+	if regionUi.focused {
+		return ZoomOut()
+	}
+	return ZoomIn()
+}
+
+// RegionCellAt returns the region square under the screen position, if any
+func RegionCellAt(x, y int) (image.Point, bool) {
+
+	if !regionUi.focused {
+		return image.Point{}, false
+	}
+	cx := (x - int(regionUi.window.px) - 4) / regionCellSize
+	cy := (y - int(regionUi.window.py) - 30) / regionCellSize
+	inside := x >= int(regionUi.window.px)+4 && y >= int(regionUi.window.py)+30
+	return image.Point{X: cx, Y: cy}, inside && cx < sim.RegionSize && cy < sim.RegionSize
+}
+
+// MouseInRegionUi returns true if the cursor is over the region view
+func MouseInRegionUi() bool {
+	w := regionUi.window
+	return regionUi.focused && image.Pt(mx, my).In(image.Rect(int(w.px), int(w.py), int(w.px)+w.width, int(w.py)+w.height))
+}
+
+// HandleRegionClick assigns the selected citizen to work the clicked region
+// 	square or, with nobody selected, builds the chosen workplace there
+func HandleRegionClick() {
+
+	inner, ok := RegionCellAt(mx, my)
+	if !ok {
+		return
+	}
+	s := world.squares[regionUi.x][regionUi.y].Settlement
+	if s == nil {
+		return
+	}
+
+	if c := settlementUi.selectedCtz; c != nil {
+		name := c.Name
+		job, err := state.RegionJobAt(s, inner)
+		if err == nil {
+			err = c.AssignTo(state, s, job)
+		}
+		if err != nil {
+			messages.AddMessage(fmt.Sprintf("Can't assign %s: %s", name, err))
+			return
+		}
+		RefreshSettlementUi()
+		return
+	}
+
+	kind := sim.SettlementKinds[buildingsUi.chosen]
+	if kind == nil {
+		messages.AddMessage("Choose something to build from the buildings window")
+		return
+	}
+	if _, err := state.StartRegionConstruction(kind, regionUi.x, regionUi.y, inner); err != nil {
+		messages.AddMessage(fmt.Sprintf("Can't build %s: %s", kind.Name, err))
+		return
+	}
+	RefreshSettlementUi()
+}
+
+// DrawRegionUi draws the region grid. It's redrawn whenever the settlement
+// 	window changes, as it depends on the selected citizen
+func DrawRegionUi(screen *ebiten.Image) {
+
+	if !regionUi.focused {
+		return
+	}
+
+	if regionUi.window.redraw || regionUi.window.canvas == nil {
+		drawRegionCanvas()
+	}
+
+	ops := &ebiten.DrawImageOptions{}
+	ops.ColorM.Scale(1, 1, 1, 0.95)
+	ops.GeoM.Translate(regionUi.window.px, regionUi.window.py)
+	screen.DrawImage(regionUi.window.canvas, ops)
+
+	regionUi.window.redraw = false
+}
+
+// drawRegionCanvas draws the region window onto its canvas, which is only
+// 	allocated the first time
+func drawRegionCanvas() {
+
+	width := regionUi.window.width
+	height := regionUi.window.height
+
+	if regionUi.window.canvas == nil {
+		regionUi.window.canvas = ebiten.NewImage(width, height)
+	}
+	canvas := regionUi.window.canvas
+	canvas.Fill(color.Black)
+
+	home := world.squares[regionUi.x][regionUi.y].Settlement
+	titleText := fmt.Sprintf("Region %d,%d", regionUi.x, regionUi.y)
+	titleWidth := text.BoundString(fontTitle, titleText).Dx()
+	text.Draw(canvas, titleText, fontTitle, width/2-titleWidth/2, 20, color.White)
+
+	region := state.Region(regionUi.x, regionUi.y)
+	for x := 0; x < sim.RegionSize; x++ {
+		for y := 0; y < sim.RegionSize; y++ {
+			square := &region.Squares[x][y]
+			inner := image.Point{X: x, Y: y}
+			px := float64(4 + x*regionCellSize)
+			py := float64(30 + y*regionCellSize)
+
+			fill := regionGrass
+			if square.Kind == sim.TWater {
+				fill = regionWater
+			}
+			if home != nil && settlementUi.selectedCtz != nil {
+				if _, err := state.RegionJobAt(home, inner); err == nil {
+					ebitenutil.DrawRect(canvas, px, py, regionCellSize, regionCellSize, regionAvailable)
+				}
+			}
+			ebitenutil.DrawRect(canvas, px+1, py+1, regionCellSize-2, regionCellSize-2, fill)
+
+			// one letter for whatever is there, capitals for buildings
+			label := ""
+			switch {
+			case inner == sim.RegionCentre && home != nil:
+				ebitenutil.DrawRect(canvas, px+1, py+1, regionCellSize-2, regionCellSize-2, regionCentre)
+				label = strings.ToUpper(home.Kind.Name[:1])
+			case square.Settlement != nil:
+				label = strings.ToUpper(square.Settlement.Kind.Name[:1])
+				if !square.Settlement.Completed {
+					label += "?"
+				}
+			case square.Resource != nil:
+				label = square.Resource.Name[:1]
+			}
+			text.Draw(canvas, label, fontDetail, int(px)+7, int(py)+15, color.White)
+		}
+	}
+
+	y := 30 + sim.RegionSize*regionCellSize + 14
+	output := "nothing"
+	if home != nil {
+		output = state.RegionOutput(regionUi.x, regionUi.y).Describe()
+	}
+	text.Draw(canvas, fmt.Sprintf("Output: %s", output), fontDetail, 4, y, color.White)
+	y += 12
+	text.Draw(canvas, "click to assign the selected citizen, or", fontSmall, 4, y, color.Gray{Y: 160})
+	y += 8
+	text.Draw(canvas, "to build the chosen workplace. Z zooms out", fontSmall, 4, y, color.Gray{Y: 160})
+}
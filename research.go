@@ -0,0 +1,149 @@
+package main
+
+import (
+	"fmt"
+	"image/color"
+	"strings"
+
+	"github.com/hajimehoshi/ebiten"
+	"github.com/hajimehoshi/ebiten/text"
+	"github.com/jakestanley/golang-civclone/sim"
+)
+
+// ResearchUi lets the player pick what to research next
+type ResearchUi struct {
+	window  *Window
+	focused bool
+	// techButtons are the techs that can be researched right now
+	techButtons []*Button
+	techs       []*sim.Tech
+}
+
+var researchUi ResearchUi
+
+func CreateResearchUi() {
+
+	researchUi.focused = true
+	researchUi.window.redraw = true
+	researchUi.window.buttons = []*Button{}
+	researchUi.techButtons = []*Button{}
+	researchUi.techs = []*sim.Tech{}
+
+	for _, id := range state.AvailableTechs() {
+		tech := sim.Techs[id]
+		b, _ := CreateButton(&btn, tech.Name, 0, 0)
+		b.executable = true
+		b.selected = state.Research.Current == id
+		b.exec = func() string {
+			if err := state.SetResearch(tech.ID); err != nil {
+				messages.AddMessage(fmt.Sprintf("Can't research %s: %s", tech.Name, err))
+				return err.Error()
+			}
+			RefreshResearchUi()
+			return fmt.Sprintf("Researching %s", tech.Name)
+		}
+		b.SetWindow(researchUi.window)
+		researchUi.techButtons = append(researchUi.techButtons, b)
+		researchUi.techs = append(researchUi.techs, tech)
+	}
+}
+
+func ClearResearchUi() {
+	researchUi.focused = false
+	researchUi.techButtons = []*Button{}
+	researchUi.window.Destroy()
+}
+
+// ToggleResearchUi opens the research window, or closes it if it's open
+func ToggleResearchUi() string {
+	if researchUi.focused {
+		ClearResearchUi()
+		return "Closed research"
+	}
+	CreateResearchUi()
+	return "Opened research"
+}
+
+// RefreshResearchUi recreates the research UI, if it's open, to reflect
+// 	newly available techs
+func RefreshResearchUi() {
+	if researchUi.focused {
+		ClearResearchUi()
+		CreateResearchUi()
+	}
+}
+
+// ResearchStatus describes what's being researched for the main UI
+func ResearchStatus() string {
+
+	r := state.Research
+	income := state.Production()[sim.ResResearch]
+	if r.Current == "" {
+		return fmt.Sprintf("Research: nothing (%.2f banked, +%.2f)", r.Banked, income)
+	}
+
+	tech := sim.Techs[r.Current]
+	return fmt.Sprintf("Research: %s %.2f/%.1f (+%.2f)", tech.Name, r.Progress[tech.ID], tech.Cost, income)
+}
+
+// NextEpochStatus describes what's needed to enter the next age
+func NextEpochStatus() string {
+
+	needs := state.NextEpochNeeds()
+	if needs == nil {
+		return "Final age"
+	} else if len(needs) == 0 {
+		return "Next age: at the end of the year"
+	}
+	return fmt.Sprintf("Next age: %s", strings.Join(needs, ", "))
+}
+
+func DrawResearchUi(screen *ebiten.Image) {
+
+	if !researchUi.focused {
+		return
+	}
+
+	if researchUi.window.redraw || researchUi.window.canvas == nil {
+
+		width := researchUi.window.width
+		height := researchUi.window.height
+
+		canvas := ebiten.NewImage(width, height)
+		canvas.Fill(color.Black)
+
+		titleText := "Research"
+		titleWidth := text.BoundString(fontTitle, titleText).Dx()
+		text.Draw(canvas, titleText, fontTitle, width/2-titleWidth/2, 20, color.White)
+
+		x := 4
+		y := 40
+
+		if len(researchUi.techButtons) == 0 {
+			text.Draw(canvas, "Nothing to research", fontDetail, x, y, color.White)
+		}
+
+		// each tech gets a button, with its cost and what it allows beneath
+		for i, b := range researchUi.techButtons {
+			tech := researchUi.techs[i]
+			b.DrawButtonAt(canvas, x, y)
+			y += 22
+
+			detail := fmt.Sprintf("%.2f/%.1f", state.Research.Progress[tech.ID], tech.Cost)
+			for _, e := range tech.Enables() {
+				detail += fmt.Sprintf(", %s", e)
+			}
+			text.Draw(canvas, detail, fontSmall, x, y, color.Gray{Y: 160})
+			y += 8
+		}
+
+		researchUi.window.canvas = canvas
+	}
+
+	ops := &ebiten.DrawImageOptions{}
+	ops.ColorM.Scale(1, 1, 1, 0.95)
+	ops.GeoM.Translate(researchUi.window.px, researchUi.window.py)
+	screen.DrawImage(researchUi.window.canvas, ops)
+
+	researchUi.window.redraw = false
+}
@@ -0,0 +1,152 @@
+package sim
+
+const (
+	// OutcomeExtinction is the outcome of a game lost by everyone dying
+	OutcomeExtinction = "extinction"
+	// OutcomeCatastrophe is the outcome of a game lost to a hazard
+	OutcomeCatastrophe = "catastrophe"
+
+	// AnnihilationEpoch is the epoch from which hazards can end the world
+	AnnihilationEpoch = EpochAtomic
+	// hazardCasualties is the yearly chance of each citizen being killed by
+	// 	the smaller accidents of a hazard, i.e leaks and fallout, for every
+	// 	chance of it ending the world
+	hazardCasualties = 0.5
+)
+
+// Hazard is something that could end the world. It's active once its tech
+// 	has been researched and, if it has one, its building has been completed
+type Hazard struct {
+	ID string
+	// Tech is the ID of the tech that brings the hazard, if any
+	Tech string
+	// Building is the ID of the settlement kind that brings the hazard, if
+	// 	any. Every completed one adds Risk
+	Building string
+	// Risk is the chance each year of it ending the world
+	Risk float64
+	// Flavour is how it ended the world
+	Flavour string
+}
+
+var (
+	// functionally constant
+
+	// Hazards is everything that could end the world, in the order the risk
+	// 	is rolled against
+	Hazards = []*Hazard{
+		{
+			ID:      "meltdown",
+			Tech:    TechNuclearPower,
+			Risk:    0.005,
+			Flavour: "A chain of reactor meltdowns poisoned the land",
+		},
+		{
+			ID:      "nuclear war",
+			Tech:    TechNuclearPower,
+			Risk:    0.005,
+			Flavour: "Somebody pressed the button",
+		},
+		{
+			ID:      "climate",
+			Tech:    TechCombustion,
+			Risk:    0.002,
+			Flavour: "The climate collapsed under the smoke of a thousand engines",
+		},
+		{
+			ID:      "plague",
+			Tech:    TechGeneticEngineering,
+			Risk:    0.01,
+			Flavour: "An engineered plague escaped the lab",
+		},
+		{
+			ID:       "engine",
+			Building: SkEngine,
+			Risk:     0.02,
+			Flavour:  "The spaceship's engine went off on the ground",
+		},
+	}
+)
+
+// HazardRisk is the chance of the hazard ending the world this year
+func (g *GameState) HazardRisk(h *Hazard) float64 {
+
+	if !g.Research.Has(h.Tech) {
+		return 0
+	}
+	if h.Building == "" {
+		return h.Risk
+	}
+
+	risk := 0.0
+	for _, s := range g.World.FindSettlements(h.Building) {
+		if s.Completed {
+			risk += h.Risk
+		}
+	}
+	return risk
+}
+
+// AnnihilationRisk is the chance of the world ending this year, 0-1
+func (g *GameState) AnnihilationRisk() float64 {
+
+	if g.Epoch < AnnihilationEpoch {
+		return 0
+	}
+
+	risk := 0.0
+	for _, h := range Hazards {
+		risk += g.HazardRisk(h)
+	}
+	return clamp(risk, 0, 1)
+}
+
+// EnvironmentRisk is the yearly chance of each citizen being killed by the
+// 	hazards that haven't ended the world, yet
+func (g *GameState) EnvironmentRisk() float64 {
+	return clamp(g.AnnihilationRisk()*hazardCasualties, 0, 1)
+}
+
+// ProcessAnnihilation rolls for the end of the world. If it happens, the
+// 	hazard that did it is picked in proportion to its share of the risk
+func (g *GameState) ProcessAnnihilation(report *TurnReport) {
+
+	risk := g.AnnihilationRisk()
+	if risk <= 0 {
+		return
+	}
+
+	roll := g.Rng.Float64()
+	if roll >= risk {
+		return
+	}
+
+	// the roll falls inside one of the hazards' shares
+	for _, h := range Hazards {
+		roll -= g.HazardRisk(h)
+		if roll < 0 {
+			g.Outcome = OutcomeCatastrophe
+			g.Catastrophe = h.ID
+			report.AddMessage("%s", h.Flavour)
+			return
+		}
+	}
+}
+
+// ProcessExtinction ends the game if there's nobody left
+func (g *GameState) ProcessExtinction(report *TurnReport) {
+	if g.Population() == 0 {
+		g.Outcome = OutcomeExtinction
+		report.AddMessage("The last of your people has died")
+	}
+}
+
+// CatastropheFlavour is how the world ended, if it was a catastrophe
+func (g *GameState) CatastropheFlavour() string {
+	for _, h := range Hazards {
+		if h.ID == g.Catastrophe {
+			return h.Flavour
+		}
+	}
+	return ""
+}
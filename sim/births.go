@@ -0,0 +1,197 @@
+package sim
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+const (
+	// MinParentAge is the youngest a citizen can have a child
+	MinParentAge = 16
+	// MaxMotherAge is the oldest a woman can have a child
+	MaxMotherAge = 45
+	// MaxFatherAge is the oldest a man can father a child
+	MaxFatherAge = 60
+	// birthChance is the yearly chance of a fertile woman giving birth in
+	// 	a settlement with plenty of food and room
+	birthChance = 0.3
+	// hungryBirthModifier is applied to the birth chance when food is short
+	hungryBirthModifier = 0.2
+	// geneticVariance is the standard deviation of a child's genetics from
+	// 	the average of their parents
+	geneticVariance = 5
+	// inbreedingPenalty is how much genetics a child of parents who are as
+	// 	closely related as possible loses
+	inbreedingPenalty = 80
+	// MaxGenetics is as good as genetics get
+	MaxGenetics = 150
+)
+
+// Birth is a citizen that was born during a turn
+type Birth struct {
+	Name       string
+	Mother     string
+	Father     string
+	Settlement *Settlement
+}
+
+func (c *Citizen) IsFertile() bool {
+	if c.Age < MinParentAge {
+		return false
+	}
+	if c.Gender == "female" {
+		return c.Age <= MaxMotherAge
+	}
+	return c.Age <= MaxFatherAge
+}
+
+// Relatedness returns the inbreeding coefficient of a child of a and b. 0.25
+// 	for parent and child or full siblings, 0.125 for half siblings and 0 if
+// 	they're not closely related, or we don't know
+func Relatedness(a, b *Citizen) float64 {
+
+	if a.ID == 0 || b.ID == 0 {
+		return 0
+	}
+
+	for _, p := range a.Parents {
+		if p == b.ID {
+			return 0.25
+		}
+	}
+	for _, p := range b.Parents {
+		if p == a.ID {
+			return 0.25
+		}
+	}
+
+	shared := 0
+	for _, pa := range a.Parents {
+		for _, pb := range b.Parents {
+			if pa != 0 && pa == pb {
+				shared++
+			}
+		}
+	}
+
+	return float64(shared) * 0.125
+}
+
+// InheritGenetics works out a child's genetics from their parents, with
+// 	some variance and a penalty for inbreeding
+func (g *GameState) InheritGenetics(mother, father *Citizen) int {
+
+	genetics := float64(mother.Genetics+father.Genetics) / 2
+	genetics += g.Rng.NormFloat64() * geneticVariance
+	genetics -= Relatedness(mother, father) * 4 * inbreedingPenalty
+
+	return int(clamp(math.Round(genetics), 0, MaxGenetics))
+}
+
+// ChildName picks a name for a child. If someone in the settlement already
+// 	has the name, the child gets an ordinal, i.e "Seth the Second"
+func (g *GameState) ChildName(s *Settlement, gender string) string {
+
+	names := FirstNamesMale
+	if gender == "female" {
+		names = FirstNamesFemale
+	}
+	name := names[g.Rng.Intn(len(names))]
+
+	count := 0
+	for _, c := range s.Citizens {
+		if c.Name == name || strings.HasPrefix(c.Name, name+" the ") {
+			count++
+		}
+	}
+
+	switch {
+	case count == 0:
+		return name
+	case count < len(Ordinals):
+		return fmt.Sprintf("%s the %s", name, Ordinals[count])
+	default:
+		return fmt.Sprintf("%s the %d", name, count+1)
+	}
+}
+
+// ProcessBirths gives each fertile woman a chance of having a child with a
+// 	random fertile man in her settlement. Births are less likely when food
+// 	is short or homes are running out, and stop entirely when there are no
+// 	homes left
+func (g *GameState) ProcessBirths(report *TurnReport) {
+
+	for _, s := range g.World.Settlements {
+
+		if !s.Completed || len(s.Citizens) == 0 {
+			continue
+		}
+
+		// indexes, as appending children may move the citizens. Children
+		// 	join the settlement as they're born so the next child's name
+		// 	takes theirs into account
+		mothers := []int{}
+		fathers := []int{}
+		for i := range s.Citizens {
+			if !s.Citizens[i].IsFertile() {
+				continue
+			}
+			if s.Citizens[i].Gender == "female" {
+				mothers = append(mothers, i)
+			} else {
+				fathers = append(fathers, i)
+			}
+		}
+
+		if len(fathers) == 0 {
+			continue
+		}
+
+		chance := birthChance
+		if g.FoodSupply(s) < 0 {
+			chance *= hungryBirthModifier
+		}
+
+		for _, m := range mothers {
+
+			room := s.Room()
+			if room <= 0 {
+				break
+			}
+
+			// the fuller the settlement, the fewer children people have
+			if g.Rng.Float64() >= chance*float64(room)/float64(s.Kind.Popcap) {
+				continue
+			}
+
+			mother := &s.Citizens[m]
+			father := &s.Citizens[fathers[g.Rng.Intn(len(fathers))]]
+
+			gender := "male"
+			if g.Rng.Intn(2) == 0 {
+				gender = "female"
+			}
+
+			child := Citizen{
+				ID:            g.World.CreateCitizenID(),
+				Name:          g.ChildName(s, gender),
+				Gender:        gender,
+				Age:           0,
+				Genetics:      g.InheritGenetics(mother, father),
+				Proficiencies: CreateProficiencies(),
+				Parents:       []int{mother.ID, father.ID},
+			}
+			report.Births = append(report.Births, Birth{
+				Name:       child.Name,
+				Mother:     mother.Name,
+				Father:     father.Name,
+				Settlement: s,
+			})
+			report.AddMessage("%s was born to %s and %s", child.Name, mother.Name, father.Name)
+			g.Stats.Births++
+
+			s.Citizens = append(s.Citizens, child)
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package sim
+
+import "testing"
+
+// oneName makes every child get the same name until the returned func is
+// 	called
+func oneName(name string) func() {
+	male, female := FirstNamesMale, FirstNamesFemale
+	FirstNamesMale, FirstNamesFemale = []string{name}, []string{name}
+	return func() {
+		FirstNamesMale, FirstNamesFemale = male, female
+	}
+}
+
+func TestChildName(t *testing.T) {
+	defer oneName("Seth")()
+
+	tests := []struct {
+		name     string
+		existing []string
+		want     string
+	}{
+		{name: "nobody else", want: "Seth"},
+		{name: "a namesake", existing: []string{"Seth"}, want: "Seth the Second"},
+		{name: "two namesakes", existing: []string{"Seth", "Seth the Second"}, want: "Seth the Third"},
+		{name: "similar names", existing: []string{"Sethany", "Seth Rogen"}, want: "Seth"},
+	}
+
+	for _, tt := range tests {
+		g, _ := createFlatGame(t)
+		s := build(g, SkVillage, 0, 0, true)
+		for _, name := range tt.existing {
+			s.Citizens = append(s.Citizens, Citizen{Name: name})
+		}
+		if got := g.ChildName(s, "male"); got != tt.want {
+			t.Errorf("%s: named %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestSameNameBirthsInOneTurn(t *testing.T) {
+	defer oneName("Seth")()
+
+	g, _ := createFlatGame(t)
+	// lots of room and parents, so there are plenty of births in one turn
+	kind := *SettlementKinds[SkVillage]
+	kind.Popcap = 1000
+	s := build(g, SkVillage, 0, 0, true)
+	s.Kind = &kind
+	fill(g, s, 200)
+	g.Stocks[ResFood] = 10000
+
+	report := &TurnReport{}
+	g.ProcessBirths(report)
+	if len(report.Births) < 2 {
+		t.Fatalf("%d born, want at least 2", len(report.Births))
+	}
+
+	names := map[string]bool{}
+	for _, c := range s.Citizens[200:] {
+		if names[c.Name] {
+			t.Errorf("two children born this turn are called %s", c.Name)
+		}
+		names[c.Name] = true
+	}
+}
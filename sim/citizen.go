@@ -0,0 +1,83 @@
+package sim
+
+import (
+	"fmt"
+	"strings"
+)
+
+// BaseWorkingAge is the age children can take roles in the first epoch. It
+// 	rises with some epochs, see EpochTransition
+const BaseWorkingAge = 10
+
+type Citizen struct {
+	ID        int
+	Name      string
+	Age       int
+	Gender    string
+	Education int
+	Genetics  int
+	// Sick is how many more years the citizen will be ill for, 0 if they're
+	// 	well. The sick can't work
+	Sick int
+	// Homeless citizens live in a settlement with no home for them, see
+	// 	UpdateHousing
+	Homeless bool
+	// Job is what the citizen is doing, nil if they're idle
+	Job           *Job
+	Proficiencies map[string]float64
+	// Parents are the IDs of the citizen's parents, if they were born in game
+	Parents []int
+	// TODO home settlement, tile on last turn
+	// home settlement could provide a buff to effort
+}
+
+func CreateProficiencies() map[string]float64 {
+
+	p := make(map[string]float64)
+	for _, v := range ResourceTypes {
+		p[v.Job] = 0.0
+	}
+	p[SkillBuilding] = 0.0
+	return p
+}
+
+func (c *Citizen) ToString() string {
+	return fmt.Sprintf("%s, %s, %d, %s", c.Name, c.Gender, c.Age, c.JobKind())
+}
+
+func (c *Citizen) ToTerseString() string {
+	// %s for strings, %c for chars
+	return fmt.Sprintf("Citizen, %c%d", strings.ToUpper(c.Gender)[0], c.Age)
+}
+
+// CanWork returns an error explaining why the citizen can't take a role in
+// 	the given epoch, or nil if they can
+func (c *Citizen) CanWork(epoch int) error {
+	if c.Age < WorkingAge(epoch) {
+		return fmt.Errorf("too young to work until %d", WorkingAge(epoch))
+	}
+	return nil
+}
+
+// Work does a year of work gathering or researching at the citizen's job
+func (c *Citizen) Work(g *GameState, report *TurnReport) {
+	target := c.Job.Target
+	square := g.JobSquare(*c.Job)
+	resource := square.Workable()
+	if resource == nil {
+		// whatever was here has gone
+		c.Unassign()
+		return
+	}
+
+	// TODO morale bonus?
+	amount := square.Deplete(c.Yield(g, resource))
+	if resource.Produces == ResResearch {
+		g.AddResearch(amount)
+	} else {
+		g.Stocks[resource.Produces] += amount
+	}
+	if !square.HasResource() && !square.HasCompletedSettlement() {
+		report.AddMessage("The %s at %d,%d has been used up", resource.Name, target.X, target.Y)
+	}
+}
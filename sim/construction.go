@@ -0,0 +1,189 @@
+package sim
+
+import (
+	"fmt"
+	"image"
+	"math"
+)
+
+// Construction is an entry in a settlement's construction queue, for a
+// 	neighbouring construction its idle citizens help with. Entries nearer
+// 	the front of the queue have priority
+type Construction struct {
+	Target image.Point `json:"target"`
+	// Workers is how many of the settlement's idle citizens build it. 0
+	// 	means it shares whatever effort is left over with the other 0s
+	Workers int `json:"workers"`
+}
+
+// ConstructionQueue returns the settlement's queue as it should be now.
+// 	Finished or removed constructions are dropped and new ones go on the end
+func (g *GameState) ConstructionQueue(s *Settlement) []Construction {
+
+	w := g.World
+	adjacent := w.GetAdjacentUncompletedSettlements(s.WorldX, s.WorldY)
+	queued := map[image.Point]bool{}
+
+	queue := []Construction{}
+	for _, entry := range s.Queue {
+		for _, a := range adjacent {
+			if entry.Target == (image.Point{X: a.WorldX, Y: a.WorldY}) {
+				queue = append(queue, entry)
+				queued[entry.Target] = true
+			}
+		}
+	}
+	for _, a := range adjacent {
+		p := image.Point{X: a.WorldX, Y: a.WorldY}
+		if !queued[p] {
+			queue = append(queue, Construction{Target: p})
+		}
+	}
+	return queue
+}
+
+// SyncQueue brings the settlement's stored queue up to date
+func (g *GameState) SyncQueue(s *Settlement) {
+	s.Queue = g.ConstructionQueue(s)
+}
+
+// queueIndex finds the target in the settlement's queue
+func (g *GameState) queueIndex(s *Settlement, target image.Point) (int, error) {
+
+	g.SyncQueue(s)
+	for i, entry := range s.Queue {
+		if entry.Target == target {
+			return i, nil
+		}
+	}
+	return -1, fmt.Errorf("nothing being built at %d,%d", target.X, target.Y)
+}
+
+// Prioritise moves the construction at target one place up the settlement's
+// 	queue
+func (g *GameState) Prioritise(s *Settlement, target image.Point) error {
+
+	i, err := g.queueIndex(s, target)
+	if err != nil {
+		return err
+	}
+	if i == 0 {
+		return fmt.Errorf("already the top priority")
+	}
+	s.Queue[i-1], s.Queue[i] = s.Queue[i], s.Queue[i-1]
+	return nil
+}
+
+// SetWorkers sets how many of the settlement's idle citizens build the
+// 	construction at target
+func (g *GameState) SetWorkers(s *Settlement, target image.Point, workers int) error {
+
+	i, err := g.queueIndex(s, target)
+	if err != nil {
+		return err
+	}
+	if workers < 0 || workers > BuildCapacity {
+		return fmt.Errorf("between 0 and %d citizens can build it", BuildCapacity)
+	}
+	s.Queue[i].Workers = workers
+	return nil
+}
+
+// PlanConstruction splits the effort of the settlement's idle citizens
+// 	across its queue. Entries with workers get them in order of priority,
+// 	then whoever's left shares their effort between the entries without.
+// 	If every entry has workers, anyone left over doesn't build
+func (g *GameState) PlanConstruction(s *Settlement) map[*Settlement]float64 {
+	plan, _ := g.planConstruction(s)
+	return plan
+}
+
+// planConstruction is PlanConstruction, also returning the IDs of the idle
+// 	citizens whose effort was used
+func (g *GameState) planConstruction(s *Settlement) (map[*Settlement]float64, []int) {
+
+	w := g.World
+	plan := map[*Settlement]float64{}
+
+	type builder struct {
+		id     int
+		effort float64
+	}
+	efforts := []builder{}
+	for i := range s.Citizens {
+		c := &s.Citizens[i]
+		if g.JobValid(c, s) && c.JobKind() != JobIdle {
+			continue
+		}
+		if effort := c.CalculateEffort(g.Epoch, SkillBuilding); effort > 0 {
+			efforts = append(efforts, builder{id: c.ID, effort: effort})
+		}
+	}
+
+	used := []int{}
+
+	shared := []*Settlement{}
+	for _, entry := range g.ConstructionQueue(s) {
+		target := w.Squares[entry.Target.X][entry.Target.Y].Settlement
+		if entry.Workers == 0 {
+			shared = append(shared, target)
+			continue
+		}
+		for n := 0; n < entry.Workers && len(efforts) > 0; n++ {
+			plan[target] += efforts[0].effort
+			used = append(used, efforts[0].id)
+			efforts = efforts[1:]
+		}
+	}
+
+	if len(shared) > 0 {
+		leftover := 0.0
+		for _, b := range efforts {
+			leftover += b.effort
+			used = append(used, b.id)
+		}
+		for _, target := range shared {
+			plan[target] += leftover / float64(len(shared))
+		}
+	}
+
+	return plan, used
+}
+
+// ConstructionForecast is how much effort each construction will get at the
+// 	end of this year, from builders and every settlement's idle citizens
+func (g *GameState) ConstructionForecast() map[*Settlement]float64 {
+
+	w := g.World
+	forecast := map[*Settlement]float64{}
+
+	for _, s := range w.Settlements {
+		for i := range s.Citizens {
+			c := &s.Citizens[i]
+			if c.JobKind() == JobBuild && g.JobValid(c, s) {
+				target := g.JobSquare(*c.Job).Settlement
+				forecast[target] += c.CalculateEffort(g.Epoch, SkillBuilding)
+			}
+		}
+		for target, effort := range g.PlanConstruction(s) {
+			forecast[target] += effort
+		}
+	}
+
+	return forecast
+}
+
+// TurnsToComplete predicts how many turns the construction will take at
+// 	the rate of effort in the forecast, which callers work out once and
+// 	share as it's expensive. Returns -1 if nobody is building it
+func (g *GameState) TurnsToComplete(s *Settlement, forecast map[*Settlement]float64) int {
+
+	if s.Completed {
+		return 0
+	}
+	effort := forecast[s]
+	if effort <= 0 {
+		return -1
+	}
+	return int(math.Ceil((1 - s.Progress) / effort))
+}
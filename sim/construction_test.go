@@ -0,0 +1,156 @@
+package sim
+
+import (
+	"bytes"
+	"image"
+	"math"
+	"testing"
+)
+
+func TestConstructionQueue(t *testing.T) {
+
+	g, s := createFlatGame(t)
+	a := image.Point{X: 2, Y: 1}
+	b := image.Point{X: 3, Y: 2}
+	build(g, SkFarm, a.X, a.Y, false)
+	build(g, SkFarm, b.X, b.Y, false)
+
+	queue := g.ConstructionQueue(s)
+	if len(queue) != 2 {
+		t.Fatalf("queue is %v, want both constructions", queue)
+	}
+	first, second := queue[0].Target, queue[1].Target
+
+	if err := g.Prioritise(s, first); err == nil {
+		t.Errorf("prioritised the top priority")
+	}
+	if err := g.Prioritise(s, image.Point{X: 0, Y: 0}); err == nil {
+		t.Errorf("prioritised nothing")
+	}
+	if err := g.Prioritise(s, second); err != nil {
+		t.Fatal(err)
+	}
+	if s.Queue[0].Target != second {
+		t.Errorf("queue is %v, want %v first", s.Queue, second)
+	}
+
+	// a finished construction drops out and a new one goes on the end
+	g.World.Squares[second.X][second.Y].Settlement.Completed = true
+	c := image.Point{X: 1, Y: 2}
+	build(g, SkFarm, c.X, c.Y, false)
+	g.SyncQueue(s)
+	if len(s.Queue) != 2 || s.Queue[0].Target != first || s.Queue[1].Target != c {
+		t.Errorf("queue is %v, want %v then %v", s.Queue, first, c)
+	}
+}
+
+func TestSetWorkers(t *testing.T) {
+
+	g, s := createFlatGame(t)
+	target := image.Point{X: 2, Y: 1}
+	build(g, SkFarm, target.X, target.Y, false)
+
+	tests := []struct {
+		workers int
+		err     bool
+	}{
+		{workers: 0},
+		{workers: 1},
+		{workers: BuildCapacity},
+		{workers: -1, err: true},
+		{workers: BuildCapacity + 1, err: true},
+	}
+
+	for _, tt := range tests {
+		err := g.SetWorkers(s, target, tt.workers)
+		if (err != nil) != tt.err {
+			t.Errorf("%d workers: error is %v, want error %v", tt.workers, err, tt.err)
+		}
+	}
+	if err := g.SetWorkers(s, image.Point{X: 0, Y: 0}, 1); err == nil {
+		t.Errorf("set workers on nothing")
+	}
+}
+
+func TestPlanConstruction(t *testing.T) {
+
+	// the spawn village has 5 idle adults putting in BaseEffort each
+	tests := []struct {
+		name    string
+		workers [2]int
+		want    [2]float64
+	}{
+		{name: "all shared", workers: [2]int{0, 0}, want: [2]float64{0.25, 0.25}},
+		{name: "one with workers", workers: [2]int{2, 0}, want: [2]float64{0.2, 0.3}},
+		{name: "both with workers", workers: [2]int{2, 1}, want: [2]float64{0.2, 0.1}},
+		{name: "more workers than citizens", workers: [2]int{4, 4}, want: [2]float64{0.4, 0.1}},
+	}
+
+	for _, tt := range tests {
+		g, s := createFlatGame(t)
+		build(g, SkFarm, 2, 1, false)
+		build(g, SkFarm, 3, 2, false)
+
+		// workers and wants are in order of priority
+		targets := []*Settlement{}
+		for i, entry := range g.ConstructionQueue(s) {
+			targets = append(targets, g.World.Squares[entry.Target.X][entry.Target.Y].Settlement)
+			if err := g.SetWorkers(s, entry.Target, tt.workers[i]); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		plan := g.PlanConstruction(s)
+		for i, target := range targets {
+			if math.Abs(plan[target]-tt.want[i]) > 1e-9 {
+				t.Errorf("%s: construction %d gets %f, want %f", tt.name, i, plan[target], tt.want[i])
+			}
+		}
+	}
+}
+
+func TestTurnsToComplete(t *testing.T) {
+
+	g, s := createFlatGame(t)
+	target := build(g, SkFarm, 2, 1, false)
+
+	// 5 idle citizens put in half a farm a year
+	if turns := g.TurnsToComplete(target, g.ConstructionForecast()); turns != 2 {
+		t.Errorf("takes %d turns, want 2", turns)
+	}
+
+	for i := range s.Citizens {
+		s.Citizens[i].Age = 1
+	}
+	if turns := g.TurnsToComplete(target, g.ConstructionForecast()); turns != -1 {
+		t.Errorf("takes %d turns with nobody to build it, want -1", turns)
+	}
+
+	target.Completed = true
+	if turns := g.TurnsToComplete(target, g.ConstructionForecast()); turns != 0 {
+		t.Errorf("takes %d turns once completed, want 0", turns)
+	}
+}
+
+func TestConstructionQueueIsSaved(t *testing.T) {
+
+	g, s := createFlatGame(t)
+	build(g, SkFarm, 2, 1, false)
+	build(g, SkFarm, 3, 2, false)
+	second := g.ConstructionQueue(s)[1].Target
+	if err := g.Prioritise(s, second); err != nil {
+		t.Fatal(err)
+	}
+	if err := g.SetWorkers(s, second, 3); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := Load(bytes.NewReader(saveBytes(t, g)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	queue := loaded.ConstructionQueue(loaded.World.Settlements[0])
+	if len(queue) != 2 || queue[0].Target != second || queue[0].Workers != 3 {
+		t.Errorf("queue is %v after loading, want %v first with 3 workers", queue, second)
+	}
+}
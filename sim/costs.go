@@ -0,0 +1,198 @@
+package sim
+
+import (
+	"fmt"
+	"image"
+	"strings"
+)
+
+const (
+	// CancelRefund is the share of its cost given back when a construction
+	// 	is cancelled before it's finished
+	CancelRefund = 0.75
+	// DemolishRefund is the share of its cost given back when a completed
+	// 	settlement is demolished
+	DemolishRefund = 0.5
+)
+
+// Describe lists the amounts in stocks order, i.e "2 wood, 1 stone"
+func (s Stocks) Describe() string {
+
+	parts := []string{}
+	for _, id := range ResourceOrder {
+		if s[id] > 0 {
+			parts = append(parts, fmt.Sprintf("%g %s", s[id], Resources[id].Name))
+		}
+	}
+	if len(parts) == 0 {
+		return "free"
+	}
+	return strings.Join(parts, ", ")
+}
+
+// CanAfford returns an error explaining what there isn't enough of to pay
+// 	the cost, or nil if it can be paid
+func (g *GameState) CanAfford(cost Stocks) error {
+
+	short := []string{}
+	for _, id := range ResourceOrder {
+		if g.Stocks[id] < cost[id] {
+			short = append(short, fmt.Sprintf("%s (%.1f/%g)", Resources[id].Name, g.Stocks[id], cost[id]))
+		}
+	}
+	if len(short) > 0 {
+		return fmt.Errorf("not enough %s", strings.Join(short, ", "))
+	}
+	return nil
+}
+
+// StartConstruction pays for a settlement of the given kind and starts
+// 	building it on the empty square at x,y
+func (g *GameState) StartConstruction(kind *SettlementKind, x, y int) (*Settlement, error) {
+
+	w := g.World
+	if !w.TileIsInRange(x, y) || !w.Squares[x][y].IsEmpty() || w.Squares[x][y].Kind != TGrass {
+		return nil, fmt.Errorf("%d,%d isn't empty grass", x, y)
+	}
+	if err := g.CanBuild(kind); err != nil {
+		return nil, err
+	}
+	if err := g.CanAfford(kind.Cost); err != nil {
+		return nil, err
+	}
+
+	for id, amount := range kind.Cost {
+		g.Stocks[id] -= amount
+		g.Stats.Spend(id, amount)
+	}
+
+	s := w.CreateSettlement(kind, x, y)
+	w.Squares[x][y].Settlement = s
+	return s, nil
+}
+
+// Refund is what would be given back for removing the settlement
+func (g *GameState) Refund(s *Settlement) Stocks {
+
+	share := DemolishRefund
+	if !s.Completed {
+		share = CancelRefund
+	}
+
+	refund := Stocks{}
+	for id, amount := range s.Kind.Cost {
+		refund[id] = amount * share
+	}
+	return refund
+}
+
+// Demolition is what happened when a settlement was demolished
+type Demolition struct {
+	Refund Stocks
+	// Rehoused, Homeless and Lost are the names of the citizens who lived
+	// 	there, split by whether they found a home elsewhere, moved in
+	// 	somewhere without one or had nowhere to go at all
+	Rehoused []string
+	Homeless []string
+	Lost     []string
+	// Unassigned are the names of citizens elsewhere who were building it
+	// 	or moving there, and are now idle
+	Unassigned []string
+}
+
+// CanDemolish returns an error explaining why the settlement can't be
+// 	demolished, or nil if it can
+func (g *GameState) CanDemolish(s *Settlement) error {
+
+	if g.World.Squares[s.WorldX][s.WorldY].Settlement != s {
+		return fmt.Errorf("the %s has already gone", s.Kind.Name)
+	}
+	if !s.Completed {
+		return nil
+	}
+	for _, other := range g.World.Settlements {
+		if other != s && other.Completed && other.Kind.Popcap > 0 {
+			return nil
+		}
+	}
+	return fmt.Errorf("the %s is your last settlement", s.Kind.Name)
+}
+
+// nearest returns the settlement closest to x,y, the first listed if
+// 	several are as close
+func nearest(settlements []*Settlement, x, y int) *Settlement {
+
+	var best *Settlement
+	bestDistance := 0
+	for _, s := range settlements {
+		dx, dy := s.WorldX-x, s.WorldY-y
+		if d := dx*dx + dy*dy; best == nil || d < bestDistance {
+			best, bestDistance = s, d
+		}
+	}
+	return best
+}
+
+// Demolish removes the settlement, giving back part of what it cost. Its
+// 	citizens move to settlements with room, neighbours first, and anyone
+// 	left over moves into the nearest settlement homeless. Anyone else with
+// 	a job there is made idle and workplaces in its region are knocked down
+// 	too. Unfinished constructions are just cancelled
+func (g *GameState) Demolish(s *Settlement) (Demolition, error) {
+
+	d := Demolition{Refund: g.Refund(s), Rehoused: []string{}, Homeless: []string{}, Lost: []string{}, Unassigned: []string{}}
+	if err := g.CanDemolish(s); err != nil {
+		return d, err
+	}
+
+	w := g.World
+	homes := []*Settlement{}
+	for _, home := range append(w.GetAdjacentSettlements(s.WorldX, s.WorldY), w.Settlements...) {
+		if home != s && home.Completed && home.Kind.Popcap > 0 {
+			homes = append(homes, home)
+		}
+	}
+
+	for _, c := range s.Citizens {
+		c.Unassign()
+		var dest *Settlement
+		for _, home := range homes {
+			if home.Room() > 0 {
+				dest = home
+				break
+			}
+		}
+		switch {
+		case dest != nil:
+			d.Rehoused = append(d.Rehoused, c.Name)
+		case len(homes) > 0:
+			dest = nearest(homes, s.WorldX, s.WorldY)
+			d.Homeless = append(d.Homeless, c.Name)
+		default:
+			d.Lost = append(d.Lost, c.Name)
+			continue
+		}
+		dest.Citizens = append(dest.Citizens, c)
+		dest.UpdateHousing()
+	}
+	s.Citizens = []Citizen{}
+
+	target := image.Point{X: s.WorldX, Y: s.WorldY}
+	for _, other := range w.Settlements {
+		for i := range other.Citizens {
+			if c := &other.Citizens[i]; c.Job != nil && c.Job.Target == target {
+				c.Unassign()
+				d.Unassigned = append(d.Unassigned, c.Name)
+			}
+		}
+	}
+	// generated afresh if it's needed again
+	w.Squares[s.WorldX][s.WorldY].Region = nil
+
+	for id, amount := range d.Refund {
+		g.Stocks[id] += amount
+		g.Stats.Spend(id, -amount)
+	}
+	w.RemoveSettlement(s)
+	return d, nil
+}
@@ -0,0 +1,201 @@
+package sim
+
+import (
+	"image"
+	"testing"
+)
+
+func TestCanDemolish(t *testing.T) {
+
+	tests := []struct {
+		name string
+		// kind is built next to the spawn village, if set
+		kind      string
+		completed bool
+		// spawn demolishes the spawn village rather than what was built
+		spawn bool
+		err   bool
+	}{
+		{name: "last settlement", spawn: true, err: true},
+		{name: "another village", kind: SkVillage, completed: true, spawn: true},
+		{name: "only a farm left", kind: SkFarm, completed: true, spawn: true, err: true},
+		{name: "unfinished village", kind: SkVillage},
+		{name: "completed farm", kind: SkFarm, completed: true},
+	}
+
+	for _, tt := range tests {
+		g, target := createFlatGame(t)
+		if tt.kind != "" {
+			built := build(g, tt.kind, 0, 0, tt.completed)
+			if !tt.spawn {
+				target = built
+			}
+		}
+
+		if err := g.CanDemolish(target); (err != nil) != tt.err {
+			t.Errorf("%s: error is %v, want error %v", tt.name, err, tt.err)
+		}
+	}
+}
+
+func TestDemolishTwice(t *testing.T) {
+
+	g, _ := createFlatGame(t)
+	target := build(g, SkVillage, 0, 0, true)
+	if _, err := g.Demolish(target); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := g.Demolish(target); err == nil {
+		t.Errorf("demolished the same village twice")
+	}
+}
+
+func TestDemolishRehousesNeighboursFirst(t *testing.T) {
+
+	g, spawn := createFlatGame(t)
+	// listed before near, but not next door
+	far := build(g, SkVillage, 0, 0, true)
+	near := build(g, SkVillage, 2, 1, true)
+	target := build(g, SkVillage, 2, 0, true)
+	for _, name := range []string{"Eve", "Frank"} {
+		target.Citizens = append(target.Citizens, Citizen{ID: g.World.CreateCitizenID(), Name: name, Age: 20})
+	}
+	wood := g.Stocks[ResWood]
+	population := len(spawn.Citizens)
+
+	d, err := g.Demolish(target)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(d.Rehoused) != 2 || len(d.Homeless) != 0 || len(d.Lost) != 0 {
+		t.Errorf("rehoused %v, homeless %v, lost %v, want both rehoused", d.Rehoused, d.Homeless, d.Lost)
+	}
+	if len(near.Citizens) != 2 || len(far.Citizens) != 0 || len(spawn.Citizens) != population {
+		t.Errorf("near has %d, far %d, spawn %d, want both next door", len(near.Citizens), len(far.Citizens), len(spawn.Citizens))
+	}
+
+	if want := wood + SettlementKinds[SkVillage].Cost[ResWood]*DemolishRefund; g.Stocks[ResWood] != want {
+		t.Errorf("%f wood after demolishing, want %f", g.Stocks[ResWood], want)
+	}
+	if g.World.Squares[2][0].Settlement != nil {
+		t.Errorf("the village is still on its square")
+	}
+	for _, s := range g.World.Settlements {
+		if s == target {
+			t.Errorf("the village is still listed")
+		}
+	}
+}
+
+func TestDemolishCancelsConstruction(t *testing.T) {
+
+	g, _ := createFlatGame(t)
+	target := build(g, SkFarm, 3, 2, false)
+	wood := g.Stocks[ResWood]
+
+	if _, err := g.Demolish(target); err != nil {
+		t.Fatal(err)
+	}
+	if want := wood + SettlementKinds[SkFarm].Cost[ResWood]*CancelRefund; g.Stocks[ResWood] != want {
+		t.Errorf("%f wood after cancelling, want %f", g.Stocks[ResWood], want)
+	}
+	if g.World.Squares[3][2].Settlement != nil {
+		t.Errorf("the farm is still on its square")
+	}
+}
+
+func TestDemolishSendsTheHomelessToTheNearestSettlement(t *testing.T) {
+
+	g, spawn := createFlatGame(t)
+	// listed before near, but further away
+	far := build(g, SkVillage, 0, 0, true)
+	near := build(g, SkVillage, 3, 1, true)
+	target := build(g, SkVillage, 4, 1, true)
+
+	// everywhere is full
+	for _, s := range []*Settlement{spawn, far, near} {
+		for len(s.Citizens) < s.Kind.Popcap {
+			s.Citizens = append(s.Citizens, Citizen{ID: g.World.CreateCitizenID(), Age: 20})
+		}
+	}
+	target.Citizens = append(target.Citizens, Citizen{ID: g.World.CreateCitizenID(), Name: "Eve", Age: 20})
+
+	d, err := g.Demolish(target)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(d.Homeless) != 1 || len(d.Rehoused) != 0 || len(d.Lost) != 0 {
+		t.Fatalf("rehoused %v, homeless %v, lost %v, want Eve homeless", d.Rehoused, d.Homeless, d.Lost)
+	}
+	if near.Homeless() != 1 || far.Homeless() != 0 || spawn.Homeless() != 0 {
+		t.Errorf("homeless near %d, far %d, spawn %d, want 1 near", near.Homeless(), far.Homeless(), spawn.Homeless())
+	}
+}
+
+func TestNearest(t *testing.T) {
+
+	a := &Settlement{WorldX: 0, WorldY: 0}
+	b := &Settlement{WorldX: 4, WorldY: 4}
+	c := &Settlement{WorldX: 2, WorldY: 0}
+
+	tests := []struct {
+		name string
+		x, y int
+		want *Settlement
+	}{
+		{name: "on top of one", x: 4, y: 4, want: b},
+		{name: "closest", x: 3, y: 0, want: c},
+		{name: "a tie goes to the first listed", x: 1, y: 0, want: a},
+	}
+
+	for _, tt := range tests {
+		if got := nearest([]*Settlement{a, b, c}, tt.x, tt.y); got != tt.want {
+			t.Errorf("%s: got %d,%d", tt.name, got.WorldX, got.WorldY)
+		}
+	}
+}
+
+func TestDemolishUnassignsJobsThere(t *testing.T) {
+
+	tests := []struct {
+		name      string
+		kind      string
+		completed bool
+		job       JobKind
+	}{
+		{name: "moving to a village", kind: SkVillage, completed: true, job: JobMove},
+		{name: "building a farm", kind: SkFarm, job: JobBuild},
+	}
+
+	for _, tt := range tests {
+		g, spawn := createFlatGame(t)
+		target := build(g, tt.kind, 2, 1, tt.completed)
+		// a workplace in its region, which goes with it
+		g.Region(2, 1).Squares[0][0].Settlement = &Settlement{WorldX: 2, WorldY: 1, Kind: SettlementKinds[SkFarm], Completed: true}
+
+		spawn.Citizens[0].Job = &Job{Kind: tt.job, Target: image.Point{X: 2, Y: 1}}
+		// working in the spawn village's own region, which is left alone
+		gather := Job{Kind: JobGather, Target: image.Point{X: 2, Y: 2}, Region: true}
+		spawn.Citizens[1].Job = &gather
+
+		d, err := g.Demolish(target)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if len(d.Unassigned) != 1 || d.Unassigned[0] != spawn.Citizens[0].Name {
+			t.Errorf("%s: unassigned %v, want %s", tt.name, d.Unassigned, spawn.Citizens[0].Name)
+		}
+		if spawn.Citizens[0].Assigned() {
+			t.Errorf("%s: still has a %s job at the demolished square", tt.name, spawn.Citizens[0].JobKind())
+		}
+		if spawn.Citizens[1].Job == nil || *spawn.Citizens[1].Job != gather {
+			t.Errorf("%s: lost a job somewhere else", tt.name)
+		}
+		if g.World.Squares[2][1].Region != nil {
+			t.Errorf("%s: the region is still there", tt.name)
+		}
+		if g.Region(2, 1).Squares[0][0].Settlement != nil {
+			t.Errorf("%s: the region workplace is still there", tt.name)
+		}
+	}
+}
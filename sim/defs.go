@@ -0,0 +1,195 @@
+package sim
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	// TWater water tile type index
+	TWater = 0
+	// TGrass grass tile type index
+	TGrass = 1
+	// SkVillage settlement kind ref for villages
+	SkVillage = "VILLAGE"
+	// SkSuburb settlement kind ref for suburbs
+	SkSuburb = "SUBURB"
+	// SkFarm settlement kind ref for farms
+	SkFarm = "FARM"
+	// spaceship part settlement kind refs, see MpSpaceship
+	SkLaunchPad = "LAUNCH_PAD"
+	SkRocket    = "ROCKET"
+	SkHabitat   = "HABITAT"
+	SkEngine    = "ENGINE"
+	// SkSchool settlement kind ref for schools, where teachers work
+	SkSchool = "SCHOOL"
+	// healthcare settlement kind refs, where doctors work. Each is better
+	// 	than the last
+	SkHealersHut = "HEALERS_HUT"
+	SkInfirmary  = "INFIRMARY"
+	SkHospital   = "HOSPITAL"
+)
+
+// SettlementKind describes a type of settlement or building. Anything to do
+// 	with how it looks is up to the renderer, keyed by ID
+type SettlementKind struct {
+	ID     string
+	Name   string
+	Effort float64
+	Popcap int
+	// Produces is the ID of the resource type citizens can work here once
+	// 	it is completed, if any
+	Produces string
+	// Research is the ID of the tech needed before it can be built
+	Research string
+	// Unique kinds can only be built once
+	Unique bool
+	// Cost is the resources taken from stocks when construction starts
+	Cost Stocks
+}
+
+var (
+	// functionally constant
+
+	// SettlementKinds is every kind of settlement that can exist, keyed by ID
+	SettlementKinds = map[string]*SettlementKind{
+		SkVillage: {
+			ID:     SkVillage,
+			Name:   "village",
+			Popcap: 10,
+			// means it will take two person years to construct
+			Effort:   0.5,
+			Produces: RtStudy,
+			Cost:     Stocks{ResWood: 2},
+		},
+		SkSuburb: {
+			ID:       SkSuburb,
+			Name:     "suburb",
+			Popcap:   20,
+			Effort:   0.2,
+			Produces: RtStudy,
+			Cost:     Stocks{ResWood: 3, ResStone: 2},
+		},
+		SkFarm: {
+			ID:       SkFarm,
+			Name:     "farm",
+			Popcap:   0,
+			Effort:   0.5,
+			Produces: RtFarm,
+			Research: TechFarming,
+			Cost:     Stocks{ResWood: 1},
+		},
+		SkSchool: {
+			ID:       SkSchool,
+			Name:     "school",
+			Popcap:   0,
+			Effort:   0.5,
+			Produces: RtTeaching,
+			Research: TechWriting,
+			Cost:     Stocks{ResWood: 3, ResStone: 1},
+		},
+		SkHealersHut: {
+			ID:       SkHealersHut,
+			Name:     "healer's hut",
+			Popcap:   0,
+			Effort:   0.5,
+			Produces: RtHealing,
+			Cost:     Stocks{ResWood: 2},
+		},
+		SkInfirmary: {
+			ID:       SkInfirmary,
+			Name:     "infirmary",
+			Popcap:   0,
+			Effort:   0.3,
+			Produces: RtNursing,
+			Research: TechPhilosophy,
+			Cost:     Stocks{ResWood: 3, ResStone: 3, ResMedicine: 1},
+		},
+		SkHospital: {
+			ID:       SkHospital,
+			Name:     "hospital",
+			Popcap:   0,
+			Effort:   0.2,
+			Produces: RtSurgery,
+			Research: TechElectricity,
+			Cost:     Stocks{ResStone: 6, ResOil: 2, ResMedicine: 3},
+		},
+		SkLaunchPad: {
+			ID:       SkLaunchPad,
+			Name:     "launch pad",
+			Effort:   0.05,
+			Research: TechSpaceflight,
+			Unique:   true,
+			Cost:     Stocks{ResStone: 10},
+		},
+		SkRocket: {
+			ID:       SkRocket,
+			Name:     "rocket",
+			Effort:   0.05,
+			Research: TechSpaceflight,
+			Unique:   true,
+			Cost:     Stocks{ResStone: 5, ResOil: 5},
+		},
+		SkHabitat: {
+			ID:       SkHabitat,
+			Name:     "habitat",
+			Effort:   0.05,
+			Research: TechSpaceflight,
+			Unique:   true,
+			Cost:     Stocks{ResWood: 5, ResStone: 5},
+		},
+		SkEngine: {
+			ID:       SkEngine,
+			Name:     "engine",
+			Effort:   0.05,
+			Research: TechSpaceflight,
+			Unique:   true,
+			Cost:     Stocks{ResOil: 5, ResUranium: 2},
+		},
+	}
+
+	// SettlementKindOrder is the order settlement kinds are listed in
+	SettlementKindOrder = []string{SkVillage, SkSuburb, SkFarm, SkSchool, SkHealersHut, SkInfirmary, SkHospital, SkLaunchPad, SkRocket, SkHabitat, SkEngine}
+)
+
+// CanBuild returns an error explaining why the kind of settlement can't be
+// 	built yet, or nil if it can
+func (g *GameState) CanBuild(kind *SettlementKind) error {
+	if !g.Research.Has(kind.Research) {
+		return fmt.Errorf("%s needs %s", kind.Name, Techs[kind.Research].Name)
+	}
+	if kind.Unique && len(g.World.FindSettlements(kind.ID)) > 0 {
+		return fmt.Errorf("there can only be one %s", kind.Name)
+	}
+	return nil
+}
+
+// BuildableKinds returns the kinds of settlement that can be built right
+// 	now, in order
+func (g *GameState) BuildableKinds() []*SettlementKind {
+
+	kinds := []*SettlementKind{}
+	for _, id := range SettlementKindOrder {
+		if kind := SettlementKinds[id]; g.CanBuild(kind) == nil {
+			kinds = append(kinds, kind)
+		}
+	}
+	return kinds
+}
+
+// Requirements describes what the kind of settlement needs before it can be
+// 	built
+func (k *SettlementKind) Requirements() string {
+
+	reqs := []string{}
+	if k.Research != "" {
+		reqs = append(reqs, Techs[k.Research].Name)
+	}
+	if k.Unique {
+		reqs = append(reqs, "only one")
+	}
+	if len(reqs) == 0 {
+		return "none"
+	}
+	return strings.Join(reqs, ", ")
+}
@@ -0,0 +1,87 @@
+package sim
+
+const (
+	// SchoolAge is the youngest children can be taught
+	SchoolAge = 5
+	// ClassSize is how many children an average teacher can keep up with
+	ClassSize = 10
+	// EducationGain is how much education a fully taught child gains in a
+	// 	year. Children in crowded classes gain less
+	EducationGain = 8
+
+	// EducationResearchBonus is the extra research done by the fully
+	// 	educated, on top of the effort bonus every job gets
+	EducationResearchBonus = 1.0
+	// EducationLearningBonus is how much faster the fully educated pick up
+	// 	skills, 1 is twice as fast
+	EducationLearningBonus = 1.0
+)
+
+// EducationShare is how educated the citizen is, 0-1
+func (c *Citizen) EducationShare() float64 {
+	return clamp(float64(c.Education)/MaxEducation, 0, 1)
+}
+
+// IsSchoolAge returns true if the citizen is old enough to be taught but too
+// 	young to work in the given epoch
+func (c *Citizen) IsSchoolAge(epoch int) bool {
+	return c.Age >= SchoolAge && c.CanWork(epoch) != nil
+}
+
+// Teaching is how many children the settlement's teachers can keep up with
+// 	this year
+func (g *GameState) Teaching(s *Settlement) float64 {
+
+	teaching := 0.0
+	for i := range s.Citizens {
+		c := &s.Citizens[i]
+		if c.JobKind() != JobTeach || !g.JobValid(c, s) {
+			continue
+		}
+		if rt := g.JobSquare(*c.Job).Workable(); rt != nil {
+			teaching += c.Yield(g, rt)
+		}
+	}
+	return teaching
+}
+
+// ProcessEducation has each settlement's teachers teach its school age
+// 	children. If there are more children than the teachers can keep up
+// 	with, everyone learns a bit less
+func (g *GameState) ProcessEducation(report *TurnReport) {
+
+	for _, s := range g.World.Settlements {
+
+		pupils := []*Citizen{}
+		for i := range s.Citizens {
+			if c := &s.Citizens[i]; c.IsSchoolAge(g.Epoch) {
+				pupils = append(pupils, c)
+			}
+		}
+		if len(pupils) == 0 {
+			continue
+		}
+
+		share := clamp(g.Teaching(s)/float64(len(pupils)), 0, 1)
+		gain := int(float64(EducationGain)*share + 0.5)
+		for _, c := range pupils {
+			c.Education += gain
+			if c.Education > MaxEducation {
+				c.Education = MaxEducation
+			}
+		}
+	}
+}
+
+// AverageEducation is the average education of the settlement's citizens
+func (s *Settlement) AverageEducation() float64 {
+
+	if len(s.Citizens) == 0 {
+		return 0
+	}
+	total := 0
+	for _, c := range s.Citizens {
+		total += c.Education
+	}
+	return float64(total) / float64(len(s.Citizens))
+}
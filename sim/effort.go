@@ -0,0 +1,121 @@
+package sim
+
+import "math"
+
+const (
+	// BaseEffort is how much an unskilled, uneducated adult gets done in a
+	// 	year at any job
+	BaseEffort = 0.1
+	// SkillBuilding is the proficiency key for construction. Other skills
+	// 	are keyed by the job name of the resource type worked
+	SkillBuilding = "building"
+
+	// ProficiencyGain is the share of the distance to full proficiency made
+	// 	up by a year of practice, so gains flatten out
+	ProficiencyGain = 0.1
+	// ProficiencyDecay is the share of a skill lost in a year it isn't used
+	ProficiencyDecay = 0.05
+	// ProficiencyBonus is the extra effort at full proficiency, 1 is double
+	ProficiencyBonus = 1.0
+
+	// MaxEducation is as educated as a citizen gets
+	MaxEducation = 100
+	// EducationBonus is the extra effort at full education
+	EducationBonus = 0.5
+
+	// GeneticsBonus is the extra effort for genetics of twice the spawn
+	// 	settlement's, or the shortfall for none at all
+	GeneticsBonus = 0.2
+	// baseGenetics is the genetics of the spawn settlement's citizens
+	baseGenetics = 100
+
+	// YouthAge is the age citizens stop working at a youth's pace
+	YouthAge = 16
+	// YouthEffort is the share of a full effort youths manage
+	YouthEffort = 0.6
+	// PrimeAge is the oldest citizens work at a full pace
+	PrimeAge = 50
+	// AgeingEffort is the share of a full effort lost each year past PrimeAge
+	AgeingEffort = 0.02
+	// MinAgeEffort is the least share of a full effort the old manage
+	MinAgeEffort = 0.3
+)
+
+// AgeEffort is the share of a full effort a working citizen of the given age
+// 	manages
+func AgeEffort(age int) float64 {
+	if age < YouthAge {
+		return YouthEffort
+	}
+	if age <= PrimeAge {
+		return 1
+	}
+	return math.Max(1-float64(age-PrimeAge)*AgeingEffort, MinAgeEffort)
+}
+
+// CalculateEffort is how much the citizen gets done in a year at a task
+// 	using the given skill, in the given epoch. Children and the sick can't
+// 	work at all and the homeless don't have the heart to work at full pace
+func (c *Citizen) CalculateEffort(epoch int, skill string) float64 {
+	if c.CanWork(epoch) != nil || c.Sick > 0 {
+		return 0
+	}
+
+	proficiency := 1 + c.Proficiencies[skill]*ProficiencyBonus
+	education := 1 + c.EducationShare()*EducationBonus
+	genetics := 1 + float64(c.Genetics-baseGenetics)/baseGenetics*GeneticsBonus
+
+	effort := BaseEffort * proficiency * education * genetics * AgeEffort(c.Age)
+	if c.Homeless {
+		effort *= HomelessEffort
+	}
+	return effort
+}
+
+// Train improves the skill the citizen used this year and lets the rest
+// 	decay. An empty skill means they didn't practise anything. The
+// 	educated learn faster
+func (c *Citizen) Train(skill string) {
+
+	gain := clamp(ProficiencyGain*(1+c.EducationShare()*EducationLearningBonus), 0, 1)
+
+	if c.Proficiencies == nil {
+		c.Proficiencies = CreateProficiencies()
+	}
+	if _, ok := c.Proficiencies[skill]; !ok && skill != "" {
+		c.Proficiencies[skill] = 0
+	}
+	for k, v := range c.Proficiencies {
+		if k == skill {
+			c.Proficiencies[k] = v + (1-v)*gain
+		} else {
+			c.Proficiencies[k] = v * (1 - ProficiencyDecay)
+		}
+	}
+}
+
+// JobSkill is the skill used doing the job, or an empty string if it
+// 	doesn't use one
+func (g *GameState) JobSkill(job Job) string {
+	switch job.Kind {
+	case JobGather, JobResearch, JobTeach, JobDoctor:
+		if rt := g.JobSquare(job).Workable(); rt != nil {
+			return rt.Job
+		}
+	case JobBuild:
+		return SkillBuilding
+	}
+	return ""
+}
+
+// JobEffort is how much the citizen would get done in a year at the job.
+// 	Idle citizens help build, so idling is building effort
+func (g *GameState) JobEffort(c *Citizen, job Job) float64 {
+	switch job.Kind {
+	case JobIdle:
+		return c.CalculateEffort(g.Epoch, SkillBuilding)
+	case JobMove:
+		return 0
+	}
+	return c.CalculateEffort(g.Epoch, g.JobSkill(job))
+}
@@ -0,0 +1,138 @@
+package sim
+
+const (
+	// epoch indexes into Epochs
+	EpochNeolithic = iota
+	EpochProtohistory
+	EpochLiterary
+	EpochRoman
+	EpochClassical
+	EpochSteam
+	EpochAtomic
+	EpochComputer
+	EpochModern
+	EpochTranshuman
+)
+
+// EpochTransition is the rule for entering an epoch and what changes when
+// 	it does
+type EpochTransition struct {
+	// To is the epoch this transition enters, from the one before it
+	To int
+	// Requires are the IDs of the techs that must all be researched
+	Requires []string
+	// WorkingAgeRise is how many years longer children spend growing up
+	WorkingAgeRise int
+	// EndsMonoliths stops monoliths from spawning from this epoch on
+	EndsMonoliths bool
+}
+
+var (
+	// functionally constant
+
+	// EpochTransitions are the rules for entering each epoch, keyed by the
+	// 	epoch entered. There is no rule for the first epoch
+	EpochTransitions = map[int]*EpochTransition{
+		EpochProtohistory: {
+			To:       EpochProtohistory,
+			Requires: []string{TechFarming},
+		},
+		EpochLiterary: {
+			To:             EpochLiterary,
+			Requires:       []string{TechWriting},
+			WorkingAgeRise: 2,
+			EndsMonoliths:  true,
+		},
+		EpochRoman: {
+			To:       EpochRoman,
+			Requires: []string{TechMathematics, TechMasonry},
+		},
+		EpochClassical: {
+			To:       EpochClassical,
+			Requires: []string{TechPhilosophy},
+		},
+		EpochSteam: {
+			To:             EpochSteam,
+			Requires:       []string{TechSteamPower},
+			WorkingAgeRise: 2,
+		},
+		EpochAtomic: {
+			To:       EpochAtomic,
+			Requires: []string{TechCombustion, TechElectricity},
+		},
+		EpochComputer: {
+			To:       EpochComputer,
+			Requires: []string{TechNuclearPower, TechSemiconductors},
+		},
+		EpochModern: {
+			To:             EpochModern,
+			Requires:       []string{TechNetworking},
+			WorkingAgeRise: 2,
+		},
+		EpochTranshuman: {
+			To:       EpochTranshuman,
+			Requires: []string{TechGeneticEngineering},
+		},
+	}
+)
+
+// WorkingAge is the age children can take roles from in an epoch. It rises
+// 	as civilisation advances and children spend longer growing up
+func WorkingAge(epoch int) int {
+
+	age := BaseWorkingAge
+	for e := 1; e <= epoch; e++ {
+		if t, ok := EpochTransitions[e]; ok {
+			age += t.WorkingAgeRise
+		}
+	}
+	return age
+}
+
+// MonolithsSpawn returns true if monoliths can still appear in the epoch
+func MonolithsSpawn(epoch int) bool {
+
+	for e := 1; e <= epoch; e++ {
+		if t, ok := EpochTransitions[e]; ok && t.EndsMonoliths {
+			return false
+		}
+	}
+	return true
+}
+
+// NextEpochNeeds returns the names of the techs still needed to enter the
+// 	next epoch, or nil if this is the last one
+func (g *GameState) NextEpochNeeds() []string {
+
+	t, ok := EpochTransitions[g.Epoch+1]
+	if !ok {
+		return nil
+	}
+
+	needs := []string{}
+	for _, id := range t.Requires {
+		if !g.Research.Has(id) {
+			needs = append(needs, Techs[id].Name)
+		}
+	}
+	return needs
+}
+
+// ProcessEpoch moves into the next epoch if everything it needs has been
+// 	discovered. Only one epoch can be entered per turn
+func (g *GameState) ProcessEpoch(report *TurnReport) {
+
+	t, ok := EpochTransitions[g.Epoch+1]
+	if !ok || len(g.NextEpochNeeds()) > 0 {
+		return
+	}
+
+	g.Epoch = t.To
+	report.AddMessage("A new age begins: %s", Epochs[g.Epoch])
+	if t.WorkingAgeRise > 0 {
+		report.AddMessage("Children now work from the age of %d", WorkingAge(g.Epoch))
+	}
+	if t.EndsMonoliths {
+		report.AddMessage("Monoliths will no longer appear")
+	}
+}
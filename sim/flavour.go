@@ -0,0 +1,79 @@
+package sim
+
+// This file contains mostly static strings such as names and flavour text
+var (
+	// functionally constant
+
+	// FirstNamesMale is a list of male names of citizens to be picked from at random.
+	FirstNamesMale = []string{
+		"Jacob",
+		"Thomas",
+		"Seth",
+		"Isaac",
+		"Aidan",
+	}
+	// FirstNamesFemale
+	FirstNamesFemale = []string{
+		"Jamie",
+		"Rebecca",
+		"Donna",
+		"Daisy",
+		"Lydia",
+	}
+	// Epochs are the names of the ages, indexed by EpochNeolithic etc
+	Epochs = []string{
+		"Neolithic Age", "Protohistory", "Literary Age",
+		"Roman Age", "Classical Age", "Age of Steam",
+		"Atomic Age", "Computer Age", "Modern Age",
+		"Transhuman Age",
+	}
+
+	// Ordinals are used to tell apart citizens with the same name, i.e
+	// 	"Seth the Second"
+	Ordinals = []string{
+		"First", "Second", "Third", "Fourth", "Fifth",
+		"Sixth", "Seventh", "Eighth", "Ninth", "Tenth",
+	}
+
+	// DeathFlavours are the messages shown when a citizen dies, keyed by
+	// 	cause. The first %s is the citizen's name and %d is their age
+	DeathFlavours = map[string][]string{
+		CauseInfancy: {
+			"Little %s didn't make it past %d",
+			"%s was taken by a fever at the age of %d",
+		},
+		CauseOldAge: {
+			"%s died peacefully in their sleep, aged %d",
+			"%s finally succumbed to old age at %d",
+			"%s went to join their ancestors at the ripe old age of %d",
+		},
+		CauseIllness: {
+			"%s coughed themselves to death at %d",
+			"%s ate some questionable berries and died, aged %d",
+			"A nasty rash claimed %s at %d",
+		},
+		CauseAccident: {
+			"%s was trampled by an aurochs at %d",
+			"%s fell out of a tree and died, aged %d",
+			"%s tried to pet a wolf. They were %d",
+		},
+		CauseStarvation: {
+			"%s starved to death at %d",
+			"%s wasted away from hunger, aged %d",
+		},
+		CauseEnvironment: {
+			"%s was lost to the elements at %d",
+			"%s died of something in the water, aged %d",
+		},
+	}
+
+	// Maybe children should be Name the Second (depending on gender)?
+	// That'd be cool and would mean I don't have to have as much variety, wew
+	// Considering gender should be random (50/50), we could end up in a situation
+	//  where there can be no more births. Could this be mitigated late game by,
+	//  I don't know... IVF/gender selection research? Initial settlement citizens
+	// 	should be hardcoded with a 50/50 distribution. Now that I think about it,
+	// 	without putting the effort in, births will be implicitly incestuous...
+	//  TH reckons I should make it a game mechanic, to affect genetics property?
+	//  It is now, see Relatedness and InheritGenetics in births.go
+)
@@ -0,0 +1,61 @@
+package sim
+
+import "math"
+
+// FoodConsumption is how much food every citizen will eat in a year.
+// 	Children eat less than adults
+func (g *GameState) FoodConsumption() float64 {
+
+	total := 0.0
+	for _, s := range g.World.Settlements {
+		for i := range s.Citizens {
+			if s.Citizens[i].CanWork(g.Epoch) != nil {
+				total += FoodPerChild
+			} else {
+				total += FoodPerCitizen
+			}
+		}
+	}
+	return total
+}
+
+// FoodProduction is how much food the citizens working farms and fishing
+// 	grounds will bring in this year, if nothing changes
+func (g *GameState) FoodProduction() float64 {
+	return g.Production()[ResFood]
+}
+
+// FoodSupply is how much food a settlement has to spare. Negative means its
+// 	citizens are going hungry, -1 being that nobody was fed at all
+func (g *GameState) FoodSupply(s *Settlement) float64 {
+	// TODO food is shared by the whole civilisation for now. local stores
+	// 	and transporting food could come later
+	if g.Hunger > 0 {
+		return -g.Hunger
+	}
+	return g.Stocks[ResFood]
+}
+
+// ProcessFeeding has everyone eat their year's food from the stocks. Any
+// 	shortfall is recorded as hunger, which feeds into deaths and births
+func (g *GameState) ProcessFeeding(report *TurnReport) {
+
+	needed := g.FoodConsumption()
+	g.Hunger = 0
+
+	if needed <= 0 {
+		return
+	}
+
+	if g.Stocks[ResFood] >= needed {
+		g.Stocks[ResFood] -= needed
+		g.Stats.Spend(ResFood, needed)
+		return
+	}
+
+	shortfall := needed - g.Stocks[ResFood]
+	g.Stats.Spend(ResFood, g.Stocks[ResFood])
+	g.Stocks[ResFood] = 0
+	g.Hunger = math.Min(shortfall/needed, 1)
+	report.AddMessage("Your people are starving! You were %.2f food short", shortfall)
+}
@@ -0,0 +1,151 @@
+package sim
+
+import "math"
+
+const (
+	// patients an average doctor can look after in each healthcare building
+	HutPatients       = 5
+	InfirmaryPatients = 10
+	HospitalPatients  = 20
+
+	// MedicinePerDoctor is how much medicine a doctor uses up in a year
+	MedicinePerDoctor = 0.1
+	// UnsuppliedCare is the share of their patients doctors can still look
+	// 	after with no medicine at all
+	UnsuppliedCare = 0.5
+
+	// SicknessChance is the yearly chance of a well citizen falling ill
+	// 	with no healthcare
+	SicknessChance = 0.05
+	// SicknessYears is how long sickness lasts with no healthcare. Full
+	// 	coverage cuts it down to a year
+	SicknessYears = 3
+)
+
+// MedicalScience is how far medicine has come by the given epoch, 0-1
+func MedicalScience(epoch int) float64 {
+	return clamp(float64(epoch)/EpochTranshuman, 0, 1)
+}
+
+// doctors returns the settlement's citizens doing a valid doctor job
+func (g *GameState) doctors(s *Settlement) []*Citizen {
+
+	doctors := []*Citizen{}
+	for i := range s.Citizens {
+		c := &s.Citizens[i]
+		if c.JobKind() == JobDoctor && g.JobValid(c, s) {
+			doctors = append(doctors, c)
+		}
+	}
+	return doctors
+}
+
+// Care is how many citizens the settlement's doctors can look after this
+// 	year, given all the medicine they need
+func (g *GameState) Care(s *Settlement) float64 {
+
+	care := 0.0
+	for _, c := range g.doctors(s) {
+		if rt := g.JobSquare(*c.Job).Workable(); rt != nil {
+			care += c.Yield(g, rt)
+		}
+	}
+	return care
+}
+
+// MedicineDemand is how much medicine every doctor at work will use up this
+// 	year. The sick don't work, so don't use any
+func (g *GameState) MedicineDemand() float64 {
+
+	demand := 0.0
+	for _, s := range g.World.Settlements {
+		for _, c := range g.doctors(s) {
+			if c.Sick == 0 {
+				demand += MedicinePerDoctor
+			}
+		}
+	}
+	return demand
+}
+
+// MedicineSupply is the share of the medicine doctors need this year that
+// 	there is in stock, 0-1
+func (g *GameState) MedicineSupply() float64 {
+
+	demand := g.MedicineDemand()
+	if demand <= 0 {
+		return 1
+	}
+	return clamp(g.Stocks[ResMedicine]/demand, 0, 1)
+}
+
+// Healthcare is the share of the settlement's citizens its doctors can look
+// 	after, 0-1. Short of medicine, doctors can look after fewer
+func (g *GameState) Healthcare(s *Settlement) float64 {
+
+	if len(s.Citizens) == 0 {
+		return 0
+	}
+	supply := UnsuppliedCare + (1-UnsuppliedCare)*g.MedicineSupply()
+	return clamp(g.Care(s)*supply/float64(len(s.Citizens)), 0, 1)
+}
+
+// SicknessLength is how many years a citizen falling ill with the given
+// 	healthcare coverage will be sick for
+func SicknessLength(coverage float64) int {
+	years := int(math.Round(SicknessYears * (1 - clamp(coverage, 0, 1)*2/3)))
+	if years < 1 {
+		return 1
+	}
+	return years
+}
+
+// SickCount is how many of the settlement's citizens are sick
+func (s *Settlement) SickCount() int {
+
+	sick := 0
+	for _, c := range s.Citizens {
+		if c.Sick > 0 {
+			sick++
+		}
+	}
+	return sick
+}
+
+// ProcessHealthcare has the sick get a year closer to recovering and some of
+// 	the well fall ill, less often and for less time where there are doctors
+// 	and more often where it's overcrowded. Then the doctors use up their
+// 	medicine
+func (g *GameState) ProcessHealthcare(report *TurnReport) {
+
+	ill := 0
+	for _, s := range g.World.Settlements {
+
+		coverage := g.Healthcare(s)
+		chance := SicknessChance * (1 - 0.5*coverage) * (1 + OvercrowdingSickness*s.Overcrowding())
+		for i := range s.Citizens {
+			c := &s.Citizens[i]
+			if c.Sick > 0 {
+				c.Sick--
+				continue
+			}
+			if g.Rng.Float64() < chance {
+				c.Sick = SicknessLength(coverage)
+				ill++
+			}
+		}
+	}
+
+	switch {
+	case ill == 1:
+		report.AddMessage("A citizen fell ill")
+	case ill > 1:
+		report.AddMessage("%d citizens fell ill", ill)
+	}
+
+	used := math.Min(g.MedicineDemand(), g.Stocks[ResMedicine])
+	if used > 0 {
+		g.Stocks[ResMedicine] -= used
+		g.Stats.Spend(ResMedicine, used)
+	}
+}
@@ -0,0 +1,94 @@
+package sim
+
+const (
+	// SpawnOccupancy is the share of the spawn village's homes filled at the
+	// 	start
+	SpawnOccupancy = 0.5
+	// HomelessEffort is the share of a full effort the homeless can muster,
+	// 	as their morale suffers
+	HomelessEffort = 0.75
+	// OvercrowdingSickness is how much more often citizens fall ill in a
+	// 	settlement with a homeless citizen for every home, 1 is twice as often
+	OvercrowdingSickness = 1.0
+)
+
+// Room is how many more citizens the settlement has homes for
+func (s *Settlement) Room() int {
+	if room := s.Kind.Popcap - len(s.Citizens); room > 0 {
+		return room
+	}
+	return 0
+}
+
+// Housed is how many of the settlement's citizens have a home
+func (s *Settlement) Housed() int {
+	return len(s.Citizens) - s.Homeless()
+}
+
+// Homeless is how many of the settlement's citizens it has no home for
+func (s *Settlement) Homeless() int {
+	if homeless := len(s.Citizens) - s.Kind.Popcap; homeless > 0 {
+		return homeless
+	}
+	return 0
+}
+
+// Overcrowding is how many homeless citizens the settlement has for each of
+// 	its homes, 0 if everyone has a home
+func (s *Settlement) Overcrowding() float64 {
+	homes := s.Kind.Popcap
+	if homes < 1 {
+		homes = 1
+	}
+	return float64(s.Homeless()) / float64(homes)
+}
+
+// UpdateHousing gives the settlement's homes to its citizens in the order
+// 	they arrived and marks the rest homeless. Returns the names of anyone
+// 	who has found a home since the last update
+func (s *Settlement) UpdateHousing() []string {
+
+	found := []string{}
+	for i := range s.Citizens {
+		c := &s.Citizens[i]
+		homeless := i >= s.Kind.Popcap
+		if c.Homeless && !homeless {
+			found = append(found, c.Name)
+		}
+		c.Homeless = homeless
+	}
+	return found
+}
+
+// Housing is how many citizens have a home and how many homes there are in
+// 	the whole civilisation
+func (g *GameState) Housing() (housed int, capacity int) {
+
+	for _, s := range g.World.Settlements {
+		if s.Completed {
+			housed += s.Housed()
+			capacity += s.Kind.Popcap
+		}
+	}
+	return housed, capacity
+}
+
+// Homeless is how many citizens in the whole civilisation have no home
+func (g *GameState) Homeless() int {
+
+	homeless := 0
+	for _, s := range g.World.Settlements {
+		homeless += s.Homeless()
+	}
+	return homeless
+}
+
+// ProcessHousing hands the homes of the dead and departed to the homeless
+func (g *GameState) ProcessHousing(report *TurnReport) {
+
+	for _, s := range g.World.Settlements {
+		for _, name := range s.UpdateHousing() {
+			report.AddMessage("%s found a home in the %s at %d,%d", name, s.Kind.Name, s.WorldX, s.WorldY)
+		}
+	}
+}
@@ -0,0 +1,190 @@
+package sim
+
+import (
+	"image"
+	"math"
+	"testing"
+)
+
+// fill adds adults to the settlement, alternating genders, until it has n
+// 	citizens
+func fill(g *GameState, s *Settlement, n int) {
+	for len(s.Citizens) < n {
+		gender := "female"
+		if len(s.Citizens)%2 == 1 {
+			gender = "male"
+		}
+		s.Citizens = append(s.Citizens, Citizen{
+			ID:            g.World.CreateCitizenID(),
+			Name:          gender,
+			Gender:        gender,
+			Age:           20,
+			Genetics:      100,
+			Proficiencies: CreateProficiencies(),
+		})
+	}
+	s.UpdateHousing()
+}
+
+func TestSpawnSettlementHasRoom(t *testing.T) {
+
+	for _, seed := range []int64{1, 2, 3} {
+		s := createTestGame(t, seed).World.Settlements[0]
+		if s.Room() < 1 || s.Homeless() != 0 {
+			t.Errorf("seed %d: spawn village has %d citizens for %d homes", seed, len(s.Citizens), s.Kind.Popcap)
+		}
+	}
+}
+
+func TestHousing(t *testing.T) {
+
+	popcap := SettlementKinds[SkVillage].Popcap
+
+	tests := []struct {
+		name         string
+		citizens     int
+		room         int
+		homeless     int
+		overcrowding float64
+	}{
+		{name: "empty", citizens: 0, room: popcap},
+		{name: "half full", citizens: popcap / 2, room: popcap - popcap/2},
+		{name: "full", citizens: popcap},
+		{name: "overcrowded", citizens: popcap + 2, homeless: 2, overcrowding: 2 / float64(popcap)},
+		{name: "twice over", citizens: popcap * 2, homeless: popcap, overcrowding: 1},
+	}
+
+	for _, tt := range tests {
+		g, _ := createFlatGame(t)
+		s := build(g, SkVillage, 0, 0, true)
+		fill(g, s, tt.citizens)
+
+		if s.Room() != tt.room {
+			t.Errorf("%s: room for %d, want %d", tt.name, s.Room(), tt.room)
+		}
+		if s.Homeless() != tt.homeless || s.Housed() != tt.citizens-tt.homeless {
+			t.Errorf("%s: %d housed and %d homeless, want %d homeless", tt.name, s.Housed(), s.Homeless(), tt.homeless)
+		}
+		if math.Abs(s.Overcrowding()-tt.overcrowding) > 1e-9 {
+			t.Errorf("%s: overcrowding is %f, want %f", tt.name, s.Overcrowding(), tt.overcrowding)
+		}
+
+		flagged := 0
+		for _, c := range s.Citizens {
+			if c.Homeless {
+				flagged++
+			}
+		}
+		if flagged != tt.homeless {
+			t.Errorf("%s: %d citizens marked homeless, want %d", tt.name, flagged, tt.homeless)
+		}
+	}
+}
+
+func TestUpdateHousingFindsHomes(t *testing.T) {
+
+	g, _ := createFlatGame(t)
+	s := build(g, SkVillage, 0, 0, true)
+	fill(g, s, s.Kind.Popcap+2)
+	first := s.Citizens[s.Kind.Popcap].Name
+
+	// the longest homeless gets the first home to come free
+	s.Citizens = s.Citizens[1:]
+	found := s.UpdateHousing()
+	if len(found) != 1 || found[0] != first {
+		t.Errorf("found homes for %v, want %s", found, first)
+	}
+	if s.Homeless() != 1 || !s.Citizens[len(s.Citizens)-1].Homeless {
+		t.Errorf("%d homeless, want the last to arrive", s.Homeless())
+	}
+	if found := s.UpdateHousing(); len(found) != 0 {
+		t.Errorf("found homes for %v again", found)
+	}
+}
+
+func TestHomelessEffort(t *testing.T) {
+
+	c := Citizen{Age: 20, Genetics: 100, Proficiencies: CreateProficiencies()}
+	housed := c.CalculateEffort(EpochNeolithic, SkillBuilding)
+	c.Homeless = true
+	homeless := c.CalculateEffort(EpochNeolithic, SkillBuilding)
+
+	if math.Abs(homeless-housed*HomelessEffort) > 1e-9 {
+		t.Errorf("homeless effort is %f, want %f", homeless, housed*HomelessEffort)
+	}
+}
+
+func TestProcessBirthsRespectsPopcap(t *testing.T) {
+
+	popcap := SettlementKinds[SkVillage].Popcap
+
+	tests := []struct {
+		name     string
+		citizens int
+	}{
+		{name: "plenty of room", citizens: 2},
+		{name: "nearly full", citizens: popcap - 1},
+		{name: "full", citizens: popcap},
+		{name: "overcrowded", citizens: popcap + 2},
+	}
+
+	for _, tt := range tests {
+		g, spawn := createFlatGame(t)
+		spawn.Citizens = []Citizen{}
+		fill(g, spawn, tt.citizens)
+		g.Stocks[ResFood] = 1000
+
+		limit := popcap
+		if tt.citizens > limit {
+			limit = tt.citizens
+		}
+		for turn := 0; turn < 50; turn++ {
+			report := &TurnReport{}
+			g.ProcessBirths(report)
+			if len(spawn.Citizens) > limit {
+				t.Fatalf("%s: %d citizens after %d turns, more than %d", tt.name, len(spawn.Citizens), turn+1, limit)
+			}
+			if tt.citizens >= popcap && len(report.Births) > 0 {
+				t.Errorf("%s: %d born with no room", tt.name, len(report.Births))
+			}
+		}
+		if tt.citizens < popcap && len(spawn.Citizens) == tt.citizens {
+			t.Errorf("%s: nobody was born", tt.name)
+		}
+	}
+}
+
+func TestProcessMovesNeedsRoom(t *testing.T) {
+
+	tests := []struct {
+		name     string
+		citizens int
+		arrives  bool
+	}{
+		{name: "room", citizens: 0, arrives: true},
+		{name: "full", citizens: SettlementKinds[SkVillage].Popcap},
+	}
+
+	for _, tt := range tests {
+		g, spawn := createFlatGame(t)
+		dest := build(g, SkVillage, 0, 0, true)
+		fill(g, dest, tt.citizens)
+
+		spawn.Citizens[0].Job = &Job{Kind: JobMove, Target: image.Point{X: 0, Y: 0}}
+		id := spawn.Citizens[0].ID
+		population := len(spawn.Citizens)
+
+		g.ProcessMoves(&TurnReport{})
+
+		arrived := len(dest.Citizens) == tt.citizens+1 && len(spawn.Citizens) == population-1
+		if arrived != tt.arrives {
+			t.Errorf("%s: arrived is %v, want %v", tt.name, arrived, tt.arrives)
+		}
+		if dest.Homeless() != 0 {
+			t.Errorf("%s: %d homeless after moving", tt.name, dest.Homeless())
+		}
+		if !tt.arrives && spawn.Citizens[0].ID == id && spawn.Citizens[0].Job != nil {
+			t.Errorf("%s: still travelling after being turned away", tt.name)
+		}
+	}
+}
@@ -0,0 +1,263 @@
+package sim
+
+import (
+	"fmt"
+	"image"
+)
+
+// JobKind is what sort of work a job is
+type JobKind string
+
+const (
+	// JobIdle citizens have no job. They help with nearby construction
+	JobIdle JobKind = "idle"
+	// JobGather citizens work a resource on their target square
+	JobGather JobKind = "gather"
+	// JobBuild citizens put all of their effort into their target
+	// 	construction
+	JobBuild JobKind = "build"
+	// JobMove citizens are moving to the settlement on their target square
+	JobMove JobKind = "move"
+	// JobResearch citizens study in their own settlement
+	JobResearch JobKind = "research"
+	// JobTeach citizens teach their settlement's children at a school
+	JobTeach JobKind = "teach"
+	// JobDoctor citizens look after their settlement's sick at a healthcare
+	// 	building
+	JobDoctor JobKind = "doctor"
+
+	// BuildCapacity is how many citizens can work on one construction
+	BuildCapacity = 4
+)
+
+// Job is a role for a citizen, done on a target square
+type Job struct {
+	Kind   JobKind     `json:"kind"`
+	Target image.Point `json:"target"`
+	// Region jobs are done at Inner in the region of the citizen's own
+	// 	settlement, which is the target
+	Region bool        `json:"region,omitempty"`
+	Inner  image.Point `json:"inner"`
+}
+
+// Where describes the square the job is done on
+func (j Job) Where() string {
+	if j.Region {
+		return fmt.Sprintf("%d,%d in the region", j.Inner.X, j.Inner.Y)
+	}
+	return fmt.Sprintf("%d,%d", j.Target.X, j.Target.Y)
+}
+
+// JobKind is what the citizen is doing. Citizens without a job are idle
+func (c *Citizen) JobKind() JobKind {
+	if c.Job == nil {
+		return JobIdle
+	}
+	return c.Job.Kind
+}
+
+// Assigned returns true if the citizen has a job
+func (c *Citizen) Assigned() bool {
+	return c.Job != nil
+}
+
+// Unassign makes the citizen idle
+func (c *Citizen) Unassign() {
+	c.Job = nil
+}
+
+// JobAt works out the job a citizen of settlement s would do on the square
+// 	at target. Returns an error if there's nothing to do there
+func (g *GameState) JobAt(s *Settlement, target image.Point) (Job, error) {
+
+	w := g.World
+	if !w.TileIsInRange(target.X, target.Y) {
+		return Job{}, fmt.Errorf("%d,%d is not on the map", target.X, target.Y)
+	}
+
+	home := image.Point{X: s.WorldX, Y: s.WorldY}
+	here := target == home
+	square := &w.Squares[target.X][target.Y]
+
+	// with transit citizens can move anywhere, but work stays local
+	if !here && !isNeighbour(home, target) {
+		if g.Research.Has(TechTransit) && square.HasCompletedSettlement() && square.Settlement.Kind.Popcap > 0 {
+			return Job{Kind: JobMove, Target: target}, nil
+		}
+		return Job{}, fmt.Errorf("%d,%d is too far away", target.X, target.Y)
+	}
+
+	job := Job{Target: target}
+
+	switch {
+	case square.Settlement != nil && !square.Settlement.Completed && !here:
+		job.Kind = JobBuild
+	case here && square.Workable() != nil && square.Workable().Produces == ResResearch:
+		job.Kind = JobResearch
+	case square.HasCompletedSettlement() && square.Settlement.Kind.Popcap > 0 && !here:
+		job.Kind = JobMove
+	case square.Workable() != nil && square.Workable().Produces == ResResearch:
+		// studying a monolith next door
+		job.Kind = JobResearch
+	case square.Workable() != nil && square.Workable().Produces == ResEducation:
+		job.Kind = JobTeach
+	case square.Workable() != nil && square.Workable().Produces == ResCare:
+		job.Kind = JobDoctor
+	case square.Workable() != nil && square.Workable().Stockpiled():
+		job.Kind = JobGather
+	default:
+		return Job{}, fmt.Errorf("nothing to do at %d,%d", target.X, target.Y)
+	}
+
+	return job, nil
+}
+
+// AvailableJobs are the jobs citizens of settlement s could take, on its
+// 	square and the ones next to it
+func (g *GameState) AvailableJobs(s *Settlement) []Job {
+
+	jobs := []Job{}
+	if !s.Completed {
+		return jobs
+	}
+
+	home := image.Point{X: s.WorldX, Y: s.WorldY}
+	targets := append([]image.Point{home}, g.World.Neighbours(home.X, home.Y)...)
+	if g.Research.Has(TechTransit) {
+		for _, other := range g.World.Settlements {
+			p := image.Point{X: other.WorldX, Y: other.WorldY}
+			if p != home && !isNeighbour(home, p) {
+				targets = append(targets, p)
+			}
+		}
+	}
+
+	for _, p := range targets {
+		if job, err := g.JobAt(s, p); err == nil {
+			jobs = append(jobs, job)
+		}
+	}
+	return jobs
+}
+
+// JobCapacity is how many citizens can do the job at once
+func (g *GameState) JobCapacity(job Job) int {
+
+	square := g.JobSquare(job)
+	switch job.Kind {
+	case JobGather, JobResearch, JobTeach, JobDoctor:
+		// region squares are small enough for one citizen each
+		if job.Region && square.Workable() != nil {
+			return 1
+		}
+		if rt := square.Workable(); rt != nil {
+			return rt.Capacity
+		}
+	case JobBuild:
+		return BuildCapacity
+	case JobMove:
+		if square.Settlement != nil {
+			return square.Settlement.Room()
+		}
+	}
+	return 0
+}
+
+// JobTaken is how many citizens are doing the job
+func (g *GameState) JobTaken(job Job) int {
+
+	taken := 0
+	for _, s := range g.World.Settlements {
+		for i := range s.Citizens {
+			if j := s.Citizens[i].Job; j != nil && *j == job {
+				taken++
+			}
+		}
+	}
+	return taken
+}
+
+// CanAssign returns an error explaining why the citizen of settlement s
+// 	can't take the job, or nil if they can
+func (g *GameState) CanAssign(c *Citizen, s *Settlement, job Job) error {
+
+	if err := c.CanWork(g.Epoch); err != nil {
+		return err
+	}
+
+	// the job has to be what's actually there now
+	actual, err := g.jobNow(s, job)
+	if err != nil {
+		return err
+	}
+	if actual != job {
+		return fmt.Errorf("can't %s at %s", job.Kind, job.Where())
+	}
+
+	square := g.JobSquare(job)
+	if rt := square.Workable(); (job.Kind == JobGather || job.Kind == JobResearch || job.Kind == JobTeach || job.Kind == JobDoctor) && !g.Research.Has(rt.Research) {
+		return fmt.Errorf("%s needs %s", rt.Job, Techs[rt.Research].Name)
+	}
+
+	// a citizen already doing it doesn't take up another place
+	if c.Job != nil && *c.Job == job {
+		return nil
+	}
+	if g.JobTaken(job) >= g.JobCapacity(job) {
+		return fmt.Errorf("no room for anyone else to %s at %s", job.Kind, job.Where())
+	}
+	return nil
+}
+
+// AssignTo gives the citizen of settlement s the job. Jobs last until they
+// 	are changed or can't be done any more. Moves that don't take a turn
+// 	happen straight away, after which c no longer points at the citizen
+func (c *Citizen) AssignTo(g *GameState, s *Settlement, job Job) error {
+
+	if err := g.CanAssign(c, s, job); err != nil {
+		return err
+	}
+	if job.Kind == JobMove && g.MoveIsInstant(s, job.Target) {
+		g.Relocate(c.ID, s, g.World.Squares[job.Target.X][job.Target.Y].Settlement)
+		return nil
+	}
+	c.Job = &job
+	return nil
+}
+
+// CheckJob makes the citizen idle if their job can no longer be done, i.e
+// 	the construction finished or the resource ran out
+func (g *GameState) CheckJob(c *Citizen, s *Settlement) {
+
+	if !g.JobValid(c, s) {
+		c.Unassign()
+	}
+}
+
+// JobValid returns true if the citizen of settlement s can still do their
+// 	job. Idle citizens always can
+func (g *GameState) JobValid(c *Citizen, s *Settlement) bool {
+
+	if c.Job == nil {
+		return true
+	}
+	if c.CanWork(g.Epoch) != nil {
+		return false
+	}
+	actual, err := g.jobNow(s, *c.Job)
+	return err == nil && actual == *c.Job
+}
+
+// jobNow works out what the job's square offers a citizen of settlement s
+// 	now, in the world or the region
+func (g *GameState) jobNow(s *Settlement, job Job) (Job, error) {
+	if job.Region {
+		return g.RegionJobAt(s, job.Inner)
+	}
+	return g.JobAt(s, job.Target)
+}
+
+func isNeighbour(a, b image.Point) bool {
+	d := a.Sub(b)
+	return (d.X == 0 && (d.Y == 1 || d.Y == -1)) || (d.Y == 0 && (d.X == 1 || d.X == -1))
+}
@@ -0,0 +1,124 @@
+package sim
+
+import (
+	"image"
+	"testing"
+)
+
+func TestJobAt(t *testing.T) {
+
+	g, s := createFlatGame(t)
+	g.World.Squares[2][1].PlaceResource(ResourceTypes[RtForest])
+	g.World.Squares[1][2].PlaceResource(ResourceTypes[RtMonolith])
+	build(g, SkVillage, 3, 2, false)
+	build(g, SkVillage, 2, 3, true)
+
+	tests := []struct {
+		name   string
+		target image.Point
+		kind   JobKind
+		err    bool
+	}{
+		{name: "research at home", target: image.Point{X: 2, Y: 2}, kind: JobResearch},
+		{name: "gather from a forest", target: image.Point{X: 2, Y: 1}, kind: JobGather},
+		{name: "study a monolith", target: image.Point{X: 1, Y: 2}, kind: JobResearch},
+		{name: "build a construction", target: image.Point{X: 3, Y: 2}, kind: JobBuild},
+		{name: "move to a village", target: image.Point{X: 2, Y: 3}, kind: JobMove},
+		{name: "empty grass", target: image.Point{X: 1, Y: 1}, err: true},
+		{name: "too far away", target: image.Point{X: 0, Y: 0}, err: true},
+		{name: "off the map", target: image.Point{X: 9, Y: 9}, err: true},
+	}
+
+	for _, tt := range tests {
+		job, err := g.JobAt(s, tt.target)
+		if tt.err {
+			if err == nil {
+				t.Errorf("%s: got %s, want an error", tt.name, job.Kind)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		if job.Kind != tt.kind || job.Target != tt.target {
+			t.Errorf("%s: got %s at %v, want %s at %v", tt.name, job.Kind, job.Target, tt.kind, tt.target)
+		}
+	}
+}
+
+func TestJobValid(t *testing.T) {
+
+	tests := []struct {
+		name   string
+		target image.Point
+		// change makes the job go away, or not
+		change func(g *GameState)
+		valid  bool
+	}{
+		{
+			name:   "monolith still there",
+			target: image.Point{X: 1, Y: 2},
+			change: func(g *GameState) {},
+			valid:  true,
+		},
+		{
+			name:   "monolith crumbled",
+			target: image.Point{X: 1, Y: 2},
+			change: func(g *GameState) { g.World.Squares[1][2].Deplete(1) },
+		},
+		{
+			name:   "forest cut down",
+			target: image.Point{X: 2, Y: 1},
+			change: func(g *GameState) { g.World.Squares[2][1].Resource = nil },
+		},
+		{
+			name:   "working age rose",
+			target: image.Point{X: 2, Y: 1},
+			change: func(g *GameState) { g.World.Settlements[0].Citizens[0].Age = 1 },
+		},
+	}
+
+	for _, tt := range tests {
+		g, s := createFlatGame(t)
+		g.World.Squares[2][1].PlaceResource(ResourceTypes[RtForest])
+		g.World.Squares[1][2].PlaceResource(ResourceTypes[RtMonolith])
+
+		c := &s.Citizens[0]
+		job, err := g.JobAt(s, tt.target)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if err := c.AssignTo(g, s, job); err != nil {
+			t.Fatalf("%s: assigning: %v", tt.name, err)
+		}
+
+		tt.change(g)
+		if got := g.JobValid(c, s); got != tt.valid {
+			t.Errorf("%s: valid is %v, want %v", tt.name, got, tt.valid)
+		}
+	}
+}
+
+func TestMonolithStudyProducesResearch(t *testing.T) {
+
+	g, s := createFlatGame(t)
+	g.World.Squares[1][2].PlaceResource(ResourceTypes[RtMonolith])
+
+	job, err := g.JobAt(s, image.Point{X: 1, Y: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Citizens[0].AssignTo(g, s, job); err != nil {
+		t.Fatal(err)
+	}
+	if g.Production()[ResResearch] <= g.PassiveResearch() {
+		t.Errorf("studying the monolith adds no research")
+	}
+
+	g.Stocks[ResFood] = 10
+	g.AdvanceTurn()
+	if s.Citizens[0].JobKind() != JobResearch {
+		t.Errorf("the monolith's student was made %s after a turn", s.Citizens[0].JobKind())
+	}
+}
@@ -0,0 +1,136 @@
+package sim
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	// MpSpaceship megaproject ref for the ship that wins the game
+	MpSpaceship = "spaceship"
+
+	// OutcomeVictory is the outcome of a game won by launching the spaceship
+	OutcomeVictory = "victory"
+
+	// StartYear is the year every game starts in
+	StartYear = 1
+)
+
+// Megaproject is a great work made of several buildings, each built the same
+// 	way as any other
+type Megaproject struct {
+	ID   string
+	Name string
+	// Parts are the IDs of the settlement kinds that make it up
+	Parts []string
+}
+
+// Summary is how a finished game went
+type Summary struct {
+	Outcome string
+	// Cause is how the world ended, for catastrophes
+	Cause          string
+	Years          int
+	Population     int
+	PeakPopulation int
+	Deaths         int
+	Births         int
+	// Spent is how much of each resource was used up over the game
+	Spent Stocks
+}
+
+var (
+	// functionally constant
+
+	// Megaprojects is every megaproject, keyed by ID
+	Megaprojects = map[string]*Megaproject{
+		MpSpaceship: {
+			ID:    MpSpaceship,
+			Name:  "spaceship",
+			Parts: []string{SkLaunchPad, SkRocket, SkHabitat, SkEngine},
+		},
+	}
+)
+
+// MissingParts returns the IDs of the parts of the megaproject that haven't
+// 	been completed, in order
+func (g *GameState) MissingParts(id string) []string {
+
+	missing := []string{}
+	for _, part := range Megaprojects[id].Parts {
+		completed := false
+		for _, s := range g.World.FindSettlements(part) {
+			completed = completed || s.Completed
+		}
+		if !completed {
+			missing = append(missing, part)
+		}
+	}
+	return missing
+}
+
+// NextPart returns the first part of the megaproject that hasn't been
+// 	started, or nil if they all have
+func (g *GameState) NextPart(id string) *SettlementKind {
+
+	for _, part := range Megaprojects[id].Parts {
+		if len(g.World.FindSettlements(part)) == 0 {
+			return SettlementKinds[part]
+		}
+	}
+	return nil
+}
+
+// CanLaunch returns an error explaining why the spaceship can't be launched,
+// 	or nil if it can
+func (g *GameState) CanLaunch() error {
+
+	if g.Over() {
+		return fmt.Errorf("the game is over")
+	}
+
+	missing := g.MissingParts(MpSpaceship)
+	if len(missing) > 0 {
+		names := []string{}
+		for _, id := range missing {
+			names = append(names, SettlementKinds[id].Name)
+		}
+		return fmt.Errorf("the spaceship still needs its %s", strings.Join(names, ", "))
+	}
+	return nil
+}
+
+// Launch launches the spaceship, winning the game
+func (g *GameState) Launch() error {
+
+	if err := g.CanLaunch(); err != nil {
+		return err
+	}
+	g.Outcome = OutcomeVictory
+	return nil
+}
+
+// Over returns true if the game has ended, one way or another
+func (g *GameState) Over() bool {
+	return g.Outcome != ""
+}
+
+// Summary describes how the game went, for the end screen
+func (g *GameState) Summary() Summary {
+
+	spent := CreateStocks()
+	for k, v := range g.Stats.Spent {
+		spent[k] = v
+	}
+
+	return Summary{
+		Outcome:        g.Outcome,
+		Cause:          g.CatastropheFlavour(),
+		Years:          g.Year - StartYear,
+		Population:     g.Population(),
+		PeakPopulation: g.Stats.PeakPopulation,
+		Deaths:         g.Stats.Deaths,
+		Births:         g.Stats.Births,
+		Spent:          spent,
+	}
+}
@@ -0,0 +1,80 @@
+package sim
+
+import "image"
+
+// MoveIsInstant returns true if citizens of settlement from can move to the
+// 	settlement at to without taking a turn. Husbandry makes moves to
+// 	neighbours instant and transit makes every move instant
+func (g *GameState) MoveIsInstant(from *Settlement, to image.Point) bool {
+	if g.Research.Has(TechTransit) {
+		return true
+	}
+	return g.Research.Has(TechHusbandry) && isNeighbour(image.Point{X: from.WorldX, Y: from.WorldY}, to)
+}
+
+// Relocate moves the citizen with the given ID from one settlement to
+// 	another. They arrive idle, and homeless if there's no room
+func (g *GameState) Relocate(id int, from, to *Settlement) {
+
+	for i := range from.Citizens {
+		if from.Citizens[i].ID != id {
+			continue
+		}
+		c := from.Citizens[i]
+		c.Unassign()
+		from.Citizens = append(from.Citizens[:i], from.Citizens[i+1:]...)
+		to.Citizens = append(to.Citizens, c)
+		from.UpdateHousing()
+		to.UpdateHousing()
+		return
+	}
+}
+
+// Travellers are the citizens on their way to settlement s, who will arrive
+// 	at the end of the year
+func (g *GameState) Travellers(s *Settlement) []*Citizen {
+
+	travellers := []*Citizen{}
+	target := Job{Kind: JobMove, Target: image.Point{X: s.WorldX, Y: s.WorldY}}
+	for _, other := range g.World.Settlements {
+		for i := range other.Citizens {
+			c := &other.Citizens[i]
+			if c.Job != nil && *c.Job == target {
+				travellers = append(travellers, c)
+			}
+		}
+	}
+	return travellers
+}
+
+// ProcessMoves has citizens who spent the year travelling arrive at their
+// 	new settlement, if there's still room for them when they get there
+func (g *GameState) ProcessMoves(report *TurnReport) {
+
+	w := g.World
+	for _, s := range w.Settlements {
+
+		// copied as relocating changes the list
+		citizens := append([]Citizen{}, s.Citizens...)
+		for _, c := range citizens {
+
+			if c.JobKind() != JobMove {
+				continue
+			}
+
+			dest := w.Squares[c.Job.Target.X][c.Job.Target.Y].Settlement
+			if dest == nil || !dest.Completed || dest.Room() <= 0 {
+				report.AddMessage("%s couldn't move as there was no room", c.Name)
+				for i := range s.Citizens {
+					if s.Citizens[i].ID == c.ID {
+						s.Citizens[i].Unassign()
+					}
+				}
+				continue
+			}
+
+			g.Relocate(c.ID, s, dest)
+			report.AddMessage("%s arrived at the %s at %d,%d", c.Name, dest.Kind.Name, dest.WorldX, dest.WorldY)
+		}
+	}
+}
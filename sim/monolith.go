@@ -0,0 +1,52 @@
+package sim
+
+import "image"
+
+var (
+	// functionally constant
+
+	// MonolithChances are the chances each year of a monolith appearing,
+	// 	keyed by epoch. Epochs not listed get none
+	MonolithChances = map[int]float64{
+		EpochNeolithic:    0.2,
+		EpochProtohistory: 0.1,
+	}
+)
+
+// MonolithChance is the chance each year of a monolith appearing in epoch
+func MonolithChance(epoch int) float64 {
+	if !MonolithsSpawn(epoch) {
+		return 0
+	}
+	return MonolithChances[epoch]
+}
+
+// ProcessMonoliths may place a monolith on a random empty square of the
+// 	kind monoliths spawn on
+func (g *GameState) ProcessMonoliths(report *TurnReport) {
+
+	chance := MonolithChance(g.Epoch)
+	if chance <= 0 || g.Rng.Float64() >= chance {
+		return
+	}
+
+	rt := ResourceTypes[RtMonolith]
+	w := g.World
+
+	candidates := []image.Point{}
+	for x := 0; x < w.Width(); x++ {
+		for y := 0; y < w.Height(); y++ {
+			square := &w.Squares[x][y]
+			if square.Kind == rt.SpawnOn && square.IsEmpty() {
+				candidates = append(candidates, image.Point{X: x, Y: y})
+			}
+		}
+	}
+	if len(candidates) == 0 {
+		return
+	}
+
+	p := candidates[g.Rng.Intn(len(candidates))]
+	w.Squares[p.X][p.Y].PlaceResource(rt)
+	report.AddMessage("A monolith has appeared at %d,%d", p.X, p.Y)
+}
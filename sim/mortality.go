@@ -0,0 +1,180 @@
+package sim
+
+import "math"
+
+const (
+	// causes of death
+	CauseInfancy     = "infancy"
+	CauseOldAge      = "old age"
+	CauseIllness     = "illness"
+	CauseAccident    = "accident"
+	CauseStarvation  = "starvation"
+	CauseEnvironment = "environment"
+
+	// OldAge is the age from which natural deaths are put down to old age
+	OldAge = 50
+	// starvationRisk is the extra yearly chance of death for a starving citizen
+	starvationRisk = 0.25
+	// sicknessRisk is the extra yearly chance of death for a sick citizen
+	// 	with no doctor
+	sicknessRisk = 0.05
+)
+
+// MortalityFactors are everything besides age that affects the chance of a
+// 	citizen dying in a year
+type MortalityFactors struct {
+	// Medicine is medical science progression, 0-1
+	Medicine float64
+	// Doctors is how well a settlement is covered by doctors, 0-1
+	Doctors float64
+	// FoodSupply is food left over after everyone has eaten. Negative
+	// 	means people are going hungry
+	FoodSupply float64
+	// Environment is a flat extra chance of death, i.e pollution or meltdowns
+	Environment float64
+	// Sick citizens are more likely to die of illness
+	Sick bool
+}
+
+// Death is a citizen that died during a turn
+type Death struct {
+	Name       string
+	Age        int
+	Cause      string
+	Settlement *Settlement
+}
+
+// naturalDeathChance is the chance of dying in a year from age alone. High
+// 	for infants, low for young adults and rising quickly into old age
+func naturalDeathChance(age int) float64 {
+
+	chance := 0.005 + 0.0004*math.Exp(0.09*float64(age))
+	if age < 5 {
+		chance += 0.1 / float64(age+1)
+	}
+	return math.Min(chance, 1)
+}
+
+// deathRisks returns the chance of death from each cause. Medicine and
+// 	doctors reduce natural causes, but can't do anything about hunger. Poor
+// 	genetics and sickness make natural causes more likely
+func deathRisks(age, genetics int, f MortalityFactors) map[string]float64 {
+
+	natural := naturalDeathChance(age)
+	if genetics < 100 {
+		natural *= 1 + float64(100-genetics)/100
+	}
+	natural *= 1 - (0.75 * clamp(f.Medicine, 0, 1))
+	natural *= 1 - (0.5 * clamp(f.Doctors, 0, 1))
+
+	risks := map[string]float64{
+		CauseEnvironment: math.Max(f.Environment, 0),
+	}
+
+	switch {
+	case age < 5:
+		risks[CauseInfancy] = natural
+	case age >= OldAge:
+		risks[CauseOldAge] = natural
+	default:
+		risks[CauseIllness] = natural / 2
+		risks[CauseAccident] = natural / 2
+	}
+
+	if f.Sick {
+		risks[CauseIllness] += sicknessRisk * (1 - (0.5 * clamp(f.Doctors, 0, 1)))
+	}
+
+	if f.FoodSupply < 0 {
+		risks[CauseStarvation] = starvationRisk * math.Min(-f.FoodSupply, 1)
+	}
+
+	return risks
+}
+
+// DeathChance is the chance of a citizen of the given age and genetics
+// 	dying this year
+func DeathChance(age, genetics int, f MortalityFactors) float64 {
+
+	total := 0.0
+	for _, r := range deathRisks(age, genetics, f) {
+		total += r
+	}
+	return math.Min(total, 1)
+}
+
+func clamp(v, min, max float64) float64 {
+	return math.Max(min, math.Min(max, v))
+}
+
+// MortalityFactors works out the mortality factors for a settlement
+func (g *GameState) MortalityFactors(s *Settlement) MortalityFactors {
+	return MortalityFactors{
+		Medicine:    MedicalScience(g.Epoch),
+		Doctors:     g.Healthcare(s),
+		FoodSupply:  g.FoodSupply(s),
+		Environment: g.EnvironmentRisk(),
+	}
+}
+
+// ProcessMortality ages every citizen by a year and rolls for their death.
+// 	This runs before anything productive happens in a turn so that the dead
+// 	can't work
+func (g *GameState) ProcessMortality(report *TurnReport) {
+
+	for _, s := range g.World.Settlements {
+
+		f := g.MortalityFactors(s)
+		survivors := []Citizen{}
+
+		for _, c := range s.Citizens {
+			c.Age++
+
+			f.Sick = c.Sick > 0
+			cause := g.rollDeath(c.Age, c.Genetics, f)
+			if cause == "" {
+				survivors = append(survivors, c)
+				continue
+			}
+
+			death := Death{
+				Name:       c.Name,
+				Age:        c.Age,
+				Cause:      cause,
+				Settlement: s,
+			}
+			report.Deaths = append(report.Deaths, death)
+			report.AddMessage(g.DeathFlavour(death), c.Name, c.Age)
+			g.Stats.Deaths++
+		}
+
+		s.Citizens = survivors
+	}
+}
+
+// rollDeath returns the cause of death, or an empty string if the citizen
+// 	survives the year. Causes are iterated in a fixed order so that the
+// 	same seed always kills the same people
+func (g *GameState) rollDeath(age, genetics int, f MortalityFactors) string {
+
+	risks := deathRisks(age, genetics, f)
+	roll := g.Rng.Float64()
+
+	for _, cause := range []string{CauseStarvation, CauseEnvironment, CauseInfancy, CauseOldAge, CauseIllness, CauseAccident} {
+		roll -= risks[cause]
+		if roll < 0 {
+			return cause
+		}
+	}
+	return ""
+}
+
+// DeathFlavour picks a message format for a death, expecting the citizen's
+// 	name and age
+func (g *GameState) DeathFlavour(d Death) string {
+	flavours := DeathFlavours[d.Cause]
+	if len(flavours) == 0 {
+		return "%s died at %d"
+	}
+	return flavours[g.Rng.Intn(len(flavours))]
+}
@@ -0,0 +1,175 @@
+package sim
+
+import "testing"
+
+func TestEnvironmentRisk(t *testing.T) {
+
+	tests := []struct {
+		name  string
+		epoch int
+		techs []string
+		risk  bool
+	}{
+		{name: "before any hazards", epoch: EpochNeolithic},
+		{name: "hazard before the atomic age", epoch: EpochSteam, techs: []string{TechCombustion}},
+		{name: "combustion", epoch: EpochAtomic, techs: []string{TechCombustion}, risk: true},
+		{name: "nuclear power", epoch: EpochComputer, techs: []string{TechNuclearPower}, risk: true},
+	}
+
+	for _, tt := range tests {
+		g, s := createFlatGame(t)
+		g.Epoch = tt.epoch
+		for _, tech := range tt.techs {
+			g.Research.Done[tech] = true
+		}
+
+		f := g.MortalityFactors(s)
+		if got := f.Environment > 0; got != tt.risk {
+			t.Errorf("%s: environment is %f, want risk %v", tt.name, f.Environment, tt.risk)
+		}
+		if f.Environment != g.EnvironmentRisk() {
+			t.Errorf("%s: environment is %f, want %f", tt.name, f.Environment, g.EnvironmentRisk())
+		}
+		if risks := deathRisks(30, 100, f); risks[CauseEnvironment] != f.Environment {
+			t.Errorf("%s: environmental death risk is %f, want %f", tt.name, risks[CauseEnvironment], f.Environment)
+		}
+	}
+}
+
+func TestEnvironmentKillsCitizens(t *testing.T) {
+
+	g, _ := createFlatGame(t)
+	f := MortalityFactors{FoodSupply: 1, Environment: 1}
+	if cause := g.rollDeath(30, 100, f); cause != CauseEnvironment {
+		t.Errorf("certain environmental death was %q", cause)
+	}
+}
+
+func TestNaturalDeathChance(t *testing.T) {
+
+	// infants are at more risk than young adults, and from 5 it rises with age
+	if naturalDeathChance(0) <= naturalDeathChance(20) {
+		t.Errorf("newborns are safer than 20 year olds")
+	}
+	if naturalDeathChance(80) <= naturalDeathChance(20) {
+		t.Errorf("80 year olds are safer than 20 year olds")
+	}
+	for age := 6; age < 150; age++ {
+		if naturalDeathChance(age) < naturalDeathChance(age-1) {
+			t.Errorf("%d year olds are safer than %d year olds", age, age-1)
+		}
+		if chance := naturalDeathChance(age); chance > 1 {
+			t.Errorf("%d year olds have a %f chance of death", age, chance)
+		}
+	}
+}
+
+func TestDeathRisks(t *testing.T) {
+
+	tests := []struct {
+		name     string
+		age      int
+		genetics int
+		f        MortalityFactors
+		// causes are the causes of death there should be a risk of
+		causes []string
+	}{
+		{name: "infant", age: 1, genetics: 100, causes: []string{CauseInfancy}},
+		{name: "adult", age: 30, genetics: 100, causes: []string{CauseIllness, CauseAccident}},
+		{name: "elder", age: OldAge, genetics: 100, causes: []string{CauseOldAge}},
+		{name: "starving", age: 30, genetics: 100, f: MortalityFactors{FoodSupply: -1},
+			causes: []string{CauseIllness, CauseAccident, CauseStarvation}},
+		{name: "polluted", age: 30, genetics: 100, f: MortalityFactors{Environment: 0.1},
+			causes: []string{CauseIllness, CauseAccident, CauseEnvironment}},
+	}
+
+	for _, tt := range tests {
+		risks := deathRisks(tt.age, tt.genetics, tt.f)
+		want := map[string]bool{}
+		for _, cause := range tt.causes {
+			want[cause] = true
+		}
+		for cause, risk := range risks {
+			if got := risk > 0; got != want[cause] {
+				t.Errorf("%s: %s risk is %f, want risk %v", tt.name, cause, risk, want[cause])
+			}
+		}
+		for _, cause := range tt.causes {
+			if _, ok := risks[cause]; !ok {
+				t.Errorf("%s: no risk of %s", tt.name, cause)
+			}
+		}
+	}
+}
+
+func TestDeathChanceFactors(t *testing.T) {
+
+	base := DeathChance(30, 100, MortalityFactors{})
+
+	tests := []struct {
+		name     string
+		genetics int
+		f        MortalityFactors
+		// safer is whether the factors should lower the chance of death
+		safer bool
+	}{
+		{name: "medicine", genetics: 100, f: MortalityFactors{Medicine: 1}, safer: true},
+		{name: "doctors", genetics: 100, f: MortalityFactors{Doctors: 1}, safer: true},
+		{name: "poor genetics", genetics: 50},
+		{name: "sick", genetics: 100, f: MortalityFactors{Sick: true}},
+		{name: "hungry", genetics: 100, f: MortalityFactors{FoodSupply: -0.5}},
+	}
+
+	for _, tt := range tests {
+		chance := DeathChance(30, tt.genetics, tt.f)
+		if got := chance < base; got != tt.safer || chance == base {
+			t.Errorf("%s: chance of death is %f against %f, want safer %v", tt.name, chance, base, tt.safer)
+		}
+	}
+
+	// doctors help the sick, but can't feed the hungry
+	sick := MortalityFactors{Sick: true}
+	treated := MortalityFactors{Sick: true, Doctors: 1}
+	if DeathChance(30, 100, treated) >= DeathChance(30, 100, sick) {
+		t.Errorf("doctors don't help the sick")
+	}
+	hungry := deathRisks(30, 100, MortalityFactors{FoodSupply: -1})
+	fed := deathRisks(30, 100, MortalityFactors{FoodSupply: -1, Doctors: 1, Medicine: 1})
+	if hungry[CauseStarvation] != fed[CauseStarvation] {
+		t.Errorf("doctors and medicine change the risk of starvation")
+	}
+}
+
+func TestProcessMortality(t *testing.T) {
+
+	g, s := createFlatGame(t)
+	ages := map[int]int{}
+	for _, c := range s.Citizens {
+		ages[c.ID] = c.Age
+	}
+	population := len(s.Citizens)
+
+	report := &TurnReport{}
+	g.ProcessMortality(report)
+
+	if len(s.Citizens)+len(report.Deaths) != population {
+		t.Errorf("%d survived and %d died, out of %d", len(s.Citizens), len(report.Deaths), population)
+	}
+	if g.Stats.Deaths != len(report.Deaths) {
+		t.Errorf("%d deaths counted, want %d", g.Stats.Deaths, len(report.Deaths))
+	}
+	for _, c := range s.Citizens {
+		if c.Age != ages[c.ID]+1 {
+			t.Errorf("%s is %d, want %d", c.Name, c.Age, ages[c.ID]+1)
+		}
+	}
+
+	// nobody survives certain death
+	for i := range s.Citizens {
+		s.Citizens[i].Age = 200
+	}
+	g.ProcessMortality(&TurnReport{})
+	if len(s.Citizens) != 0 {
+		t.Errorf("%d citizens survived certain death", len(s.Citizens))
+	}
+}
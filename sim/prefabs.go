@@ -1,6 +1,4 @@
-package main
-
-import "math/rand"
+package sim
 
 const (
 	// WaterHeight default height of water tiles
@@ -13,9 +11,9 @@ func CreateWater() Square {
 
 	square := CreateSquare()
 
-	square.kind = TWater
-	square.height = WaterHeight
-	square.liquid = true
+	square.Kind = TWater
+	square.Height = WaterHeight
+	square.Liquid = true
 
 	return square
 }
@@ -24,9 +22,9 @@ func CreateGrass() Square {
 
 	square := CreateSquare()
 
-	square.kind = TGrass
-	square.height = GrassHeight
-	square.liquid = false
+	square.Kind = TGrass
+	square.Height = GrassHeight
+	square.Liquid = false
 
 	return square
 }
@@ -34,10 +32,18 @@ func CreateGrass() Square {
 func CreateWoods() Square {
 	square := CreateSquare()
 
-	square.kind = TGrass
-	square.height = GrassHeight
-	square.liquid = false
-	square.resource = resourcesTypes[rtForest]
+	square.Kind = TGrass
+	square.Height = GrassHeight
+	square.Liquid = false
+	square.Resource = ResourceTypes[RtForest]
+
+	return square
+}
+
+func CreateFishery() Square {
+	square := CreateWater()
+
+	square.Resource = ResourceTypes[RtFish]
 
 	return square
 }
@@ -57,7 +63,7 @@ func GrassWorldTiles() [][]Square {
 }
 
 // IslandWorldTiles is Kailynn's island
-func IslandWorldTiles() [][]Square {
+func IslandWorldTiles(rng *Rng) [][]Square {
 	tiles := [][]Square{
 		{CreateWater(), CreateWater(), CreateWater(), CreateWater(), CreateWater(), CreateWater(), CreateWater(), CreateWater()},
 		{CreateWater(), CreateWater(), CreateWater(), CreateGrass(), CreateGrass(), CreateWater(), CreateWater(), CreateWater()},
@@ -73,8 +79,8 @@ func IslandWorldTiles() [][]Square {
 	for x := 0; x < len(tiles); x++ {
 		for y := 0; y < len(tiles[x]); y++ {
 			tile := &tiles[x][y]
-			if tile.kind == TGrass {
-				tile.height += ((rand.Intn(4) * 2) - 2)
+			if tile.Kind == TGrass {
+				tile.Height += ((rng.Intn(4) * 2) - 2)
 			}
 		}
 	}
@@ -0,0 +1,238 @@
+package sim
+
+import (
+	"fmt"
+	"image"
+)
+
+const (
+	// RegionSize is the width and height of the grid inside every world square
+	RegionSize = 8
+
+	// RegionForestChance is the chance of each land square in a region being
+	// 	forest, whatever its parent is
+	RegionForestChance = 0.1
+	// RegionFishChance is the chance of each water square in a region having
+	// 	fish
+	RegionFishChance = 0.2
+)
+
+// RegionCentre is where a world square's own settlement stands inside its
+// 	region. Nothing else can be built there
+var RegionCentre = image.Point{X: RegionSize / 2, Y: RegionSize / 2}
+
+// Region is the grid of squares inside a world square. Citizens of the
+// 	settlement on the world square can work its resources and build
+// 	workplaces on it, and whatever they make goes into the world's stocks
+type Region struct {
+	Squares [][]Square
+}
+
+// CreateRegion generates the inside of the parent square. It has the same
+// 	terrain as its parent, with the parent's resource spread over as many
+// 	squares as it has room for workers and a scattering of the resources
+// 	that can spawn on that terrain. Deposits that run out, like monoliths,
+// 	stay on the world square only
+func CreateRegion(rng *Rng, parent *Square) *Region {
+
+	r := &Region{Squares: make([][]Square, RegionSize)}
+	for x := 0; x < RegionSize; x++ {
+		r.Squares[x] = make([]Square, RegionSize)
+		for y := 0; y < RegionSize; y++ {
+			square := CreateSquare()
+			square.Kind = parent.Kind
+			square.Height = parent.Height
+			square.Liquid = parent.Liquid
+			r.Squares[x][y] = square
+		}
+	}
+
+	if rt := parent.Resource; rt != nil && rt.Deposit <= 0 {
+		for placed := 0; placed < rt.Capacity; {
+			p := image.Point{X: rng.Intn(RegionSize), Y: rng.Intn(RegionSize)}
+			if square := &r.Squares[p.X][p.Y]; p != RegionCentre && square.IsEmpty() {
+				square.PlaceResource(rt)
+				placed++
+			}
+		}
+	}
+
+	// the same scattering as world generation, in fixed order
+	scatter := []*ResourceType{}
+	for _, id := range ResourceTypeIDs() {
+		if rt := ResourceTypes[id]; rt.SpawnOn == parent.Kind && rt.SpawnChance > 0 && rt.Deposit <= 0 {
+			scatter = append(scatter, rt)
+		}
+	}
+
+	for x := 0; x < RegionSize; x++ {
+		for y := 0; y < RegionSize; y++ {
+			square := &r.Squares[x][y]
+			if (image.Point{X: x, Y: y}) == RegionCentre || !square.IsEmpty() {
+				continue
+			}
+			if parent.Kind == TWater {
+				if rng.Float64() < RegionFishChance {
+					square.PlaceResource(ResourceTypes[RtFish])
+				}
+				continue
+			}
+			if rng.Float64() < RegionForestChance {
+				square.PlaceResource(ResourceTypes[RtForest])
+				continue
+			}
+			for _, rt := range scatter {
+				if rng.Float64() < rt.SpawnChance {
+					square.PlaceResource(rt)
+					break
+				}
+			}
+		}
+	}
+	return r
+}
+
+// InRange returns true if the point is inside the region
+func (r *Region) InRange(p image.Point) bool {
+	return p.X >= 0 && p.X < RegionSize && p.Y >= 0 && p.Y < RegionSize
+}
+
+// regionSeed is the seed for the region of the world square at x,y in a
+// 	world height squares tall. Regions have their own streams so that
+// 	looking inside one doesn't change what happens next in the game, and
+// 	every square in a world gets a different one
+func regionSeed(seed int64, x, y, height int) int64 {
+	return seed*31 + int64(x)*int64(height) + int64(y) + 1
+}
+
+// Region returns the region inside the world square at x,y, generating it
+// 	the first time it's needed
+func (g *GameState) Region(x, y int) *Region {
+
+	square := &g.World.Squares[x][y]
+	if square.Region == nil {
+		square.Region = CreateRegion(CreateRng(regionSeed(g.Rng.Seed(), x, y, g.World.Height())), square)
+	}
+	return square.Region
+}
+
+// RegionJobAt works out the job a citizen of settlement s would do on the
+// 	square at inner in its region. Returns an error if there's nothing to
+// 	do there
+func (g *GameState) RegionJobAt(s *Settlement, inner image.Point) (Job, error) {
+
+	if !s.Completed {
+		return Job{}, fmt.Errorf("the %s hasn't been built yet", s.Kind.Name)
+	}
+	r := g.Region(s.WorldX, s.WorldY)
+	if !r.InRange(inner) {
+		return Job{}, fmt.Errorf("%d,%d is outside the region", inner.X, inner.Y)
+	}
+
+	job := Job{Target: image.Point{X: s.WorldX, Y: s.WorldY}, Region: true, Inner: inner}
+	square := &r.Squares[inner.X][inner.Y]
+
+	switch {
+	case square.Settlement != nil && !square.Settlement.Completed:
+		job.Kind = JobBuild
+	case square.Workable() != nil && square.Workable().Produces == ResEducation:
+		job.Kind = JobTeach
+	case square.Workable() != nil && square.Workable().Produces == ResCare:
+		job.Kind = JobDoctor
+	case square.Workable() != nil && square.Workable().Stockpiled():
+		job.Kind = JobGather
+	default:
+		return Job{}, fmt.Errorf("nothing to do at %d,%d in the region", inner.X, inner.Y)
+	}
+	return job, nil
+}
+
+// JobSquare is the square the job is done on, in the world or a region
+func (g *GameState) JobSquare(job Job) *Square {
+	if job.Region {
+		return &g.Region(job.Target.X, job.Target.Y).Squares[job.Inner.X][job.Inner.Y]
+	}
+	return &g.World.Squares[job.Target.X][job.Target.Y]
+}
+
+// CanBuildInRegion returns an error explaining why the kind of settlement
+// 	can't be built at inner in the region of the world square at x,y, or
+// 	nil if it can. Regions only have workplaces, and they have to be next to
+// 	the settlement or another finished workplace
+func (g *GameState) CanBuildInRegion(kind *SettlementKind, x, y int, inner image.Point) error {
+
+	if !g.World.Squares[x][y].HasCompletedSettlement() {
+		return fmt.Errorf("there's no settlement at %d,%d", x, y)
+	}
+	if kind.Popcap > 0 || kind.Produces == "" {
+		return fmt.Errorf("only workplaces can be built in a region")
+	}
+	if err := g.CanBuild(kind); err != nil {
+		return err
+	}
+
+	r := g.Region(x, y)
+	if !r.InRange(inner) || inner == RegionCentre {
+		return fmt.Errorf("can't build at %d,%d in the region", inner.X, inner.Y)
+	}
+	if square := &r.Squares[inner.X][inner.Y]; !square.IsEmpty() || square.Kind != TGrass {
+		return fmt.Errorf("%d,%d in the region isn't empty grass", inner.X, inner.Y)
+	}
+
+	for _, n := range []image.Point{{X: -1}, {X: 1}, {Y: -1}, {Y: 1}} {
+		p := inner.Add(n)
+		if p == RegionCentre || (r.InRange(p) && r.Squares[p.X][p.Y].HasCompletedSettlement()) {
+			return nil
+		}
+	}
+	return fmt.Errorf("has to be next to the settlement or another workplace")
+}
+
+// StartRegionConstruction pays for a workplace of the given kind and starts
+// 	building it at inner in the region of the world square at x,y. Region
+// 	constructions aren't in the world's settlements list, but they're
+// 	counted by FindSettlements
+func (g *GameState) StartRegionConstruction(kind *SettlementKind, x, y int, inner image.Point) (*Settlement, error) {
+
+	if err := g.CanBuildInRegion(kind, x, y, inner); err != nil {
+		return nil, err
+	}
+	if err := g.CanAfford(kind.Cost); err != nil {
+		return nil, err
+	}
+
+	for id, amount := range kind.Cost {
+		g.Stocks[id] -= amount
+		g.Stats.Spend(id, amount)
+	}
+
+	s := &Settlement{
+		WorldX:   x,
+		WorldY:   y,
+		Kind:     kind,
+		Citizens: []Citizen{},
+	}
+	g.Region(x, y).Squares[inner.X][inner.Y].Settlement = s
+	return s, nil
+}
+
+// RegionOutput is how much the citizens working in the region of the world
+// 	square at x,y will make this year
+func (g *GameState) RegionOutput(x, y int) Stocks {
+
+	output := CreateStocks()
+	s := g.World.Squares[x][y].Settlement
+	if s == nil {
+		return output
+	}
+	for i := range s.Citizens {
+		c := &s.Citizens[i]
+		if c.JobKind() != JobGather || !c.Job.Region {
+			continue
+		}
+		if rt := g.JobSquare(*c.Job).Workable(); rt != nil {
+			output[rt.Produces] += c.Yield(g, rt)
+		}
+	}
+	return output
+}
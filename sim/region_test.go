@@ -0,0 +1,94 @@
+package sim
+
+import (
+	"image"
+	"testing"
+)
+
+func TestRegionSeedsAreUnique(t *testing.T) {
+
+	tests := []struct {
+		width, height int
+	}{
+		{width: 8, height: 8},
+		{width: 2, height: 100},
+		{width: 65, height: 64},
+		{width: 100, height: 100},
+	}
+
+	for _, tt := range tests {
+		seen := map[int64]bool{}
+		for x := 0; x < tt.width; x++ {
+			for y := 0; y < tt.height; y++ {
+				seed := regionSeed(42, x, y, tt.height)
+				if seen[seed] {
+					t.Fatalf("%dx%d: %d,%d shares a region seed", tt.width, tt.height, x, y)
+				}
+				seen[seed] = true
+			}
+		}
+	}
+}
+
+func TestRegionIsStableAndDoesNotDrawFromTheGame(t *testing.T) {
+
+	g := createTestGame(t, 7)
+	s := g.World.Settlements[0]
+	draws := g.Rng.Draws()
+
+	r := g.Region(s.WorldX, s.WorldY)
+	if g.Rng.Draws() != draws {
+		t.Errorf("generating a region drew from the game's rng")
+	}
+	if g.Region(s.WorldX, s.WorldY) != r {
+		t.Errorf("the region was generated again")
+	}
+
+	// another game from the same seed has the same region
+	other := createTestGame(t, 7)
+	if !regionsEqual(r, other.Region(s.WorldX, s.WorldY)) {
+		t.Errorf("the same seed generated a different region")
+	}
+}
+
+func regionsEqual(a, b *Region) bool {
+	for x := range a.Squares {
+		for y := range a.Squares[x] {
+			sa, sb := &a.Squares[x][y], &b.Squares[x][y]
+			if sa.Kind != sb.Kind || sa.Resource != sb.Resource || sa.Remaining != sb.Remaining {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func TestFindSettlementsCountsRegions(t *testing.T) {
+
+	g, spawn := createFlatGame(t)
+	build(g, SkFarm, 2, 1, true)
+	g.Stocks[ResWood] = 10
+	g.Research.Done[SettlementKinds[SkFarm].Research] = true
+
+	var inner *Settlement
+	for _, n := range []image.Point{{X: -1}, {X: 1}, {Y: -1}, {Y: 1}} {
+		var err error
+		if inner, err = g.StartRegionConstruction(SettlementKinds[SkFarm], spawn.WorldX, spawn.WorldY, RegionCentre.Add(n)); err == nil {
+			break
+		}
+	}
+	if inner == nil {
+		t.Fatal("nowhere to build a farm in the region")
+	}
+	farms := g.World.FindSettlements(SkFarm)
+	if len(farms) != 2 || farms[1] != inner {
+		t.Errorf("found %d farms, want the one on the map and the one in the region", len(farms))
+	}
+
+	// so a unique kind built in a region can't be built again
+	unique := *SettlementKinds[SkFarm]
+	unique.Unique = true
+	if err := g.CanBuild(&unique); err == nil {
+		t.Errorf("a unique kind built in a region could be built again")
+	}
+}
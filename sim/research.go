@@ -0,0 +1,354 @@
+package sim
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+const (
+	// tech refs
+	TechFarming        = "farming"
+	TechHusbandry      = "husbandry"
+	TechMasonry        = "masonry"
+	TechWriting        = "writing"
+	TechMathematics    = "mathematics"
+	TechCombustion     = "combustion"
+	TechTransit        = "transit"
+	TechSemiconductors = "semiconductors"
+	TechNuclearPower   = "nuclear power"
+	TechPhilosophy     = "philosophy"
+	TechSteamPower     = "steam power"
+	TechElectricity    = "electricity"
+	TechNetworking     = "networking"
+	TechSpaceflight    = "spaceflight"
+	// TechGeneticEngineering tech ref
+	TechGeneticEngineering = "genetic engineering"
+
+	// ModResearch is the modifier key for bonus research output
+	ModResearch = "yield:" + ResResearch
+
+	// PassiveResearchRate is how much every working citizen works out just by
+	// 	living, before the epoch modifier
+	PassiveResearchRate = 0.05
+	// NeolithicResearchModifier scales all research in the Neolithic, when
+	// 	nobody can write anything down
+	NeolithicResearchModifier = 0.25
+)
+
+// Tech is something that can be researched. What a tech allows is found by
+// 	looking for the buildings and resource types that need it, see Enables
+type Tech struct {
+	ID   string
+	Name string
+	// Requires are the IDs of the techs that must be researched first
+	Requires []string
+	// Cost is how many research points it takes
+	Cost float64
+	// Epoch is the earliest epoch it can be researched in
+	Epoch int
+	// Modifiers are bonuses that apply once it's researched, keyed by
+	// 	modifier, i.e ModResearch or YieldModifier(ResFood)
+	Modifiers map[string]float64
+}
+
+// Research is how far the civilisation has got through the tech tree
+type Research struct {
+	// Done is every researched tech, keyed by ID
+	Done map[string]bool `json:"done"`
+	// Current is the ID of the tech being researched, if any
+	Current string `json:"current,omitempty"`
+	// Progress is how many points have gone into each unfinished tech
+	Progress map[string]float64 `json:"progress"`
+	// Banked is research done while nothing was being researched. It goes
+	// 	towards the next tech picked
+	Banked float64 `json:"banked"`
+}
+
+var (
+	// functionally constant
+
+	// Techs is the tech tree, keyed by ID
+	Techs = map[string]*Tech{
+		TechFarming: {
+			ID:   TechFarming,
+			Name: "Farming",
+			Cost: 0.5,
+		},
+		TechHusbandry: {
+			ID:       TechHusbandry,
+			Name:     "Husbandry",
+			Requires: []string{TechFarming},
+			Cost:     1,
+			// moves to adjacent settlements are instant, see MoveIsInstant
+		},
+		TechMasonry: {
+			ID:        TechMasonry,
+			Name:      "Masonry",
+			Cost:      0.5,
+			Modifiers: map[string]float64{YieldModifier(ResStone): 0.5},
+		},
+		TechWriting: {
+			ID:       TechWriting,
+			Name:     "Writing",
+			Requires: []string{TechFarming},
+			Cost:     1.5,
+			Epoch:    EpochProtohistory,
+		},
+		TechMathematics: {
+			ID:        TechMathematics,
+			Name:      "Mathematics",
+			Requires:  []string{TechWriting},
+			Cost:      3,
+			Epoch:     EpochLiterary,
+			Modifiers: map[string]float64{ModResearch: 0.25},
+		},
+		TechPhilosophy: {
+			ID:        TechPhilosophy,
+			Name:      "Philosophy",
+			Requires:  []string{TechMathematics},
+			Cost:      4,
+			Epoch:     EpochRoman,
+			Modifiers: map[string]float64{ModResearch: 0.25},
+		},
+		TechSteamPower: {
+			ID:       TechSteamPower,
+			Name:     "Steam Power",
+			Requires: []string{TechPhilosophy, TechMasonry},
+			Cost:     5,
+			Epoch:    EpochClassical,
+		},
+		TechCombustion: {
+			ID:       TechCombustion,
+			Name:     "Combustion",
+			Requires: []string{TechSteamPower},
+			Cost:     6,
+			Epoch:    EpochSteam,
+		},
+		TechElectricity: {
+			ID:       TechElectricity,
+			Name:     "Electricity",
+			Requires: []string{TechSteamPower},
+			Cost:     6,
+			Epoch:    EpochSteam,
+		},
+		TechTransit: {
+			ID:       TechTransit,
+			Name:     "Transit",
+			Requires: []string{TechSteamPower, TechHusbandry},
+			Cost:     6,
+			Epoch:    EpochSteam,
+			// moves to any settlement are instant
+		},
+		TechSemiconductors: {
+			ID:       TechSemiconductors,
+			Name:     "Semiconductors",
+			Requires: []string{TechElectricity},
+			Cost:     10,
+			Epoch:    EpochAtomic,
+		},
+		TechNuclearPower: {
+			ID:       TechNuclearPower,
+			Name:     "Nuclear Power",
+			Requires: []string{TechCombustion, TechElectricity},
+			Cost:     12,
+			Epoch:    EpochAtomic,
+		},
+		TechNetworking: {
+			ID:        TechNetworking,
+			Name:      "Networking",
+			Requires:  []string{TechSemiconductors},
+			Cost:      14,
+			Epoch:     EpochComputer,
+			Modifiers: map[string]float64{ModResearch: 0.5},
+		},
+		TechSpaceflight: {
+			ID:       TechSpaceflight,
+			Name:     "Spaceflight",
+			Requires: []string{TechNuclearPower, TechSemiconductors},
+			Cost:     20,
+			Epoch:    EpochComputer,
+		},
+		TechGeneticEngineering: {
+			ID:       TechGeneticEngineering,
+			Name:     "Genetic Engineering",
+			Requires: []string{TechNetworking},
+			Cost:     18,
+			Epoch:    EpochModern,
+		},
+	}
+
+	// TechOrder is the order techs are listed in
+	TechOrder = []string{
+		TechFarming, TechHusbandry, TechMasonry, TechWriting, TechMathematics,
+		TechPhilosophy, TechSteamPower, TechCombustion, TechElectricity,
+		TechTransit, TechSemiconductors, TechNuclearPower, TechNetworking,
+		TechSpaceflight, TechGeneticEngineering,
+	}
+)
+
+func CreateResearch() Research {
+
+	return Research{
+		Done:     map[string]bool{},
+		Progress: map[string]float64{},
+	}
+}
+
+// Has returns true if the research with the given ID has been done. No ID
+// 	means no research is needed
+func (r *Research) Has(id string) bool {
+	return id == "" || r.Done[id]
+}
+
+// YieldModifier is the modifier key for bonus yield of a resource
+func YieldModifier(resource string) string {
+	return "yield:" + resource
+}
+
+// Enables lists the names of the buildings and jobs that need the tech
+func (t *Tech) Enables() []string {
+
+	enables := []string{}
+	for _, sk := range SettlementKinds {
+		if sk.Research == t.ID {
+			enables = append(enables, sk.Name)
+		}
+	}
+	for _, rt := range ResourceTypes {
+		if rt.Research == t.ID {
+			enables = append(enables, rt.Job)
+		}
+	}
+	sort.Strings(enables)
+	return enables
+}
+
+// Modifier is the total bonus from researched techs for the modifier key
+func (g *GameState) Modifier(key string) float64 {
+
+	total := 0.0
+	for _, id := range TechOrder {
+		if g.Research.Has(id) {
+			total += Techs[id].Modifiers[key]
+		}
+	}
+	return total
+}
+
+// EpochResearchModifier scales how much research gets done in an epoch
+func EpochResearchModifier(epoch int) float64 {
+	if epoch == EpochNeolithic {
+		return NeolithicResearchModifier
+	}
+	return 1
+}
+
+// CanResearch returns an error explaining why the tech can't be researched
+// 	yet, or nil if it can
+func (g *GameState) CanResearch(id string) error {
+
+	t, ok := Techs[id]
+	if !ok {
+		return fmt.Errorf("unknown tech '%s'", id)
+	}
+	if g.Research.Has(id) {
+		return fmt.Errorf("%s has already been researched", t.Name)
+	}
+
+	missing := []string{}
+	for _, r := range t.Requires {
+		if !g.Research.Has(r) {
+			missing = append(missing, Techs[r].Name)
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("needs %s", strings.Join(missing, ", "))
+	}
+
+	if g.Epoch < t.Epoch {
+		return fmt.Errorf("not before %s", Epochs[t.Epoch])
+	}
+	return nil
+}
+
+// AvailableTechs are the IDs of the techs that can be researched now
+func (g *GameState) AvailableTechs() []string {
+
+	ids := []string{}
+	for _, id := range TechOrder {
+		if g.CanResearch(id) == nil {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// SetResearch picks the tech to research. Banked research goes towards it
+func (g *GameState) SetResearch(id string) error {
+
+	if err := g.CanResearch(id); err != nil {
+		return err
+	}
+
+	r := &g.Research
+	r.Current = id
+	r.Progress[id] += r.Banked
+	r.Banked = 0
+	return nil
+}
+
+// AddResearch puts points towards the current tech, or banks them if there
+// 	isn't one
+func (g *GameState) AddResearch(points float64) {
+
+	r := &g.Research
+	if r.Current == "" {
+		r.Banked += points
+	} else {
+		r.Progress[r.Current] += points
+	}
+}
+
+// PassiveResearch is how much research the citizens will work out this
+// 	year without anyone being assigned to it
+func (g *GameState) PassiveResearch() float64 {
+
+	workers := 0
+	for _, s := range g.World.Settlements {
+		for i := range s.Citizens {
+			if s.Citizens[i].CanWork(g.Epoch) == nil {
+				workers++
+			}
+		}
+	}
+	return float64(workers) * PassiveResearchRate * EpochResearchModifier(g.Epoch) * (1 + g.Modifier(ModResearch))
+}
+
+// ProcessResearch adds the passive research to what researchers have done
+// 	this year and completes the current tech if there is enough
+func (g *GameState) ProcessResearch(report *TurnReport) {
+
+	g.AddResearch(g.PassiveResearch())
+
+	r := &g.Research
+	if r.Current == "" {
+		return
+	}
+
+	t := Techs[r.Current]
+	if r.Progress[t.ID] < t.Cost {
+		return
+	}
+
+	// anything left over goes towards the next tech
+	r.Banked += r.Progress[t.ID] - t.Cost
+	delete(r.Progress, t.ID)
+	r.Done[t.ID] = true
+	r.Current = ""
+
+	report.AddMessage("You discovered %s", t.Name)
+	if enables := t.Enables(); len(enables) > 0 {
+		report.AddMessage("%s allows %s", t.Name, strings.Join(enables, ", "))
+	}
+}
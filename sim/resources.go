@@ -0,0 +1,323 @@
+package sim
+
+import "sort"
+
+const (
+	// stockpiled resource refs
+	ResFood     = "food"
+	ResWood     = "wood"
+	ResStone    = "stone"
+	ResMedicine = "medicine"
+	ResOil      = "oil"
+	ResUranium  = "uranium"
+	// ResResearch is produced like a resource but never stockpiled. It goes
+	// 	straight into the tech being researched
+	ResResearch = "research"
+	// ResEducation is produced by teachers and never stockpiled. It goes
+	// 	into the children of the teacher's settlement, see ProcessEducation
+	ResEducation = "education"
+	// ResCare is produced by doctors and never stockpiled. It goes into the
+	// 	health of the doctor's settlement, see Healthcare
+	ResCare = "care"
+
+	// indexes of the resource icons in img/icons/resources.png
+	IconUranium  = 0
+	IconOil      = 1
+	IconFood     = 2
+	IconMedicine = 3
+	IconWood     = 4
+	IconStone    = 5
+
+	// RtForest resource type ref for forests
+	RtForest = "forest"
+	// RtFish resource type ref for fishing grounds
+	RtFish = "fish"
+	// RtFarm resource type ref for farmland, only found on farms
+	RtFarm = "farm"
+	// RtQuarry resource type ref for stone deposits
+	RtQuarry = "quarry"
+	// RtOil resource type ref for oil fields
+	RtOil = "oil"
+	// RtUranium resource type ref for uranium deposits
+	RtUranium = "uranium"
+	// RtMonolith resource type ref for monoliths, which appear in the early
+	// 	ages and can be studied until they crumble
+	RtMonolith = "monolith"
+	// RtStudy resource type ref for research, done in villages and suburbs
+	RtStudy = "study"
+	// RtTeaching resource type ref for teaching, done in schools
+	RtTeaching = "teaching"
+	// RtHerbs resource type ref for medicinal herbs
+	RtHerbs = "herbs"
+	// healthcare resource type refs, worked by doctors in healers' huts,
+	// 	infirmaries and hospitals
+	RtHealing = "healing"
+	RtNursing = "nursing"
+	RtSurgery = "surgery"
+
+	// FoodPerCitizen is how much food an adult eats in a year
+	FoodPerCitizen = 0.1
+	// FoodPerChild is how much food a citizen too young to work eats in a year
+	FoodPerChild = 0.05
+	// StartingFood is enough to keep the spawn settlement going for a few
+	// 	years while it gets fishing or farming
+	StartingFood = 2.0
+)
+
+// Resource is something that can be stockpiled
+type Resource struct {
+	ID   string
+	Name string
+	// Icon is the index of the resource's icon, see IconFood etc
+	Icon int
+}
+
+// YieldFormula works out how much of a resource a citizen produces from the
+// 	effort they put in
+type YieldFormula func(g *GameState, effort float64) float64
+
+// LinearYield produces rate units of resource per unit of effort
+func LinearYield(rate float64) YieldFormula {
+	return func(g *GameState, effort float64) float64 {
+		return effort * rate
+	}
+}
+
+// ResearchYield is like LinearYield but scaled by the epoch, as research is
+// 	hard to come by in the early ages
+func ResearchYield(rate float64) YieldFormula {
+	return func(g *GameState, effort float64) float64 {
+		return effort * rate * EpochResearchModifier(g.Epoch)
+	}
+}
+
+// ResourceType describes a source of a resource that citizens can work,
+// 	either sitting on a square or produced by a building
+type ResourceType struct {
+	ID   string
+	Name string
+	// Produces is the ID of the resource that working this yields
+	Produces string
+	// Yield is how much is produced for the effort put in
+	Yield YieldFormula
+	// Job is what working it is called. Proficiency is per job
+	Job string
+	// Research is the ID of the research needed before it can be worked
+	Research string
+	// Capacity is how many citizens can work one square of it at once
+	Capacity int
+	// Deposit is how much can be produced from one square before it's used
+	// 	up and disappears. Zero means it never runs out
+	Deposit float64
+	// SpawnOn is the kind of square world generation scatters this on, with
+	// 	SpawnChance per square. Resources with no chance are placed some
+	// 	other way
+	SpawnOn     int
+	SpawnChance float64
+}
+
+// Stocks is how much of each resource has been stockpiled, keyed by ID
+type Stocks map[string]float64
+
+var (
+	// functionally constant
+
+	// Resources is every resource that can be stockpiled, keyed by ID
+	Resources = map[string]*Resource{
+		ResFood:     {ID: ResFood, Name: "food", Icon: IconFood},
+		ResWood:     {ID: ResWood, Name: "wood", Icon: IconWood},
+		ResStone:    {ID: ResStone, Name: "stone", Icon: IconStone},
+		ResMedicine: {ID: ResMedicine, Name: "medicine", Icon: IconMedicine},
+		ResOil:      {ID: ResOil, Name: "oil", Icon: IconOil},
+		ResUranium:  {ID: ResUranium, Name: "uranium", Icon: IconUranium},
+	}
+
+	// ResourceOrder is the order resources are listed in
+	ResourceOrder = []string{ResFood, ResWood, ResStone, ResMedicine, ResOil, ResUranium}
+
+	// ResourceTypes is every kind of resource source that can exist, keyed by ID
+	ResourceTypes = map[string]*ResourceType{
+		RtForest: {
+			ID:       RtForest,
+			Name:     "forest",
+			Produces: ResWood,
+			Yield:    LinearYield(1),
+			Job:      "wood cutting",
+			Capacity: 3,
+		},
+		RtFish: {
+			ID:       RtFish,
+			Name:     "fishing grounds",
+			Produces: ResFood,
+			Yield:    LinearYield(1.5),
+			Job:      "fishing",
+			Capacity: 2,
+		},
+		RtFarm: {
+			ID:       RtFarm,
+			Name:     "farmland",
+			Produces: ResFood,
+			Yield:    LinearYield(2.5),
+			Job:      "farming",
+			Capacity: 4,
+		},
+		RtQuarry: {
+			ID:          RtQuarry,
+			Name:        "stone deposit",
+			Produces:    ResStone,
+			Yield:       LinearYield(0.5),
+			Job:         "quarrying",
+			Capacity:    2,
+			SpawnOn:     TGrass,
+			SpawnChance: 0.04,
+		},
+		RtOil: {
+			ID:          RtOil,
+			Name:        "oil field",
+			Produces:    ResOil,
+			Yield:       LinearYield(0.5),
+			Job:         "oil drilling",
+			Capacity:    2,
+			Research:    TechCombustion,
+			SpawnOn:     TGrass,
+			SpawnChance: 0.02,
+		},
+		RtUranium: {
+			ID:          RtUranium,
+			Name:        "uranium deposit",
+			Produces:    ResUranium,
+			Yield:       LinearYield(0.1),
+			Job:         "uranium mining",
+			Capacity:    1,
+			Research:    TechNuclearPower,
+			SpawnOn:     TGrass,
+			SpawnChance: 0.01,
+		},
+		RtMonolith: {
+			ID:       RtMonolith,
+			Name:     "monolith",
+			Produces: ResResearch,
+			// monoliths teach the same in any age, that's the point of them
+			Yield:    LinearYield(2),
+			Job:      "monolith study",
+			Capacity: 3,
+			Deposit:  1,
+			SpawnOn:  TGrass,
+		},
+		RtStudy: {
+			ID:       RtStudy,
+			Name:     "study",
+			Produces: ResResearch,
+			Yield:    ResearchYield(1),
+			Job:      "research",
+			Capacity: 5,
+			Research: TechWriting,
+		},
+		RtTeaching: {
+			ID:       RtTeaching,
+			Name:     "school",
+			Produces: ResEducation,
+			// the yield is how many children each teacher can keep up with
+			Yield:    LinearYield(ClassSize / BaseEffort),
+			Job:      "teaching",
+			Capacity: 3,
+			Research: TechWriting,
+		},
+		RtHerbs: {
+			ID:          RtHerbs,
+			Name:        "herbs",
+			Produces:    ResMedicine,
+			Yield:       LinearYield(0.5),
+			Job:         "herb gathering",
+			Capacity:    2,
+			SpawnOn:     TGrass,
+			SpawnChance: 0.03,
+		},
+		// the yield of healthcare is how many citizens each doctor can look
+		// 	after, so better buildings let doctors care for more
+		RtHealing: {
+			ID:       RtHealing,
+			Name:     "healer's hut",
+			Produces: ResCare,
+			Yield:    LinearYield(HutPatients / BaseEffort),
+			Job:      "doctoring",
+			Capacity: 2,
+		},
+		RtNursing: {
+			ID:       RtNursing,
+			Name:     "infirmary",
+			Produces: ResCare,
+			Yield:    LinearYield(InfirmaryPatients / BaseEffort),
+			Job:      "doctoring",
+			Capacity: 3,
+		},
+		RtSurgery: {
+			ID:       RtSurgery,
+			Name:     "hospital",
+			Produces: ResCare,
+			Yield:    LinearYield(HospitalPatients / BaseEffort),
+			Job:      "doctoring",
+			Capacity: 5,
+		},
+	}
+)
+
+// CreateStocks creates empty stocks with an entry for every resource
+func CreateStocks() Stocks {
+	stocks := Stocks{}
+	for id := range Resources {
+		stocks[id] = 0
+	}
+	return stocks
+}
+
+// ResourceTypeIDs returns the IDs of every resource type in a fixed order, so
+// 	that anything random done per type is reproducible
+func ResourceTypeIDs() []string {
+	ids := []string{}
+	for id := range ResourceTypes {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// Stockpiled returns true if what the resource type produces goes into
+// 	stocks, rather than research, education or care
+func (rt *ResourceType) Stockpiled() bool {
+	_, ok := Resources[rt.Produces]
+	return ok
+}
+
+// Yield is how much the citizen would produce working rt this year
+func (c *Citizen) Yield(g *GameState, rt *ResourceType) float64 {
+	if c.CanWork(g.Epoch) != nil || !g.Research.Has(rt.Research) {
+		return 0
+	}
+	yield := rt.Yield(g, c.CalculateEffort(g.Epoch, rt.Job)) * (1 + g.Modifier(YieldModifier(rt.Produces)))
+	if rt.Produces == ResResearch {
+		yield *= 1 + c.EducationShare()*EducationResearchBonus
+	}
+	return yield
+}
+
+// Production is how much of each resource the citizens at work will bring in
+// 	this year, if nothing changes. Research includes what everyone works out
+// 	without being assigned to it
+func (g *GameState) Production() Stocks {
+
+	production := CreateStocks()
+	production[ResResearch] = g.PassiveResearch()
+	for _, s := range g.World.Settlements {
+		for i := range s.Citizens {
+			c := &s.Citizens[i]
+			if k := c.JobKind(); k != JobGather && k != JobResearch {
+				continue
+			}
+			if rt := g.JobSquare(*c.Job).Workable(); rt != nil {
+				production[rt.Produces] += c.Yield(g, rt)
+			}
+		}
+	}
+	return production
+}
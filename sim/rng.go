@@ -0,0 +1,68 @@
+package sim
+
+import "math/rand"
+
+// Rng is the one random stream used by the simulation. Everything random,
+// 	from world generation to turn events, must draw from it so that a game
+// 	can be reproduced from its seed
+type Rng struct {
+	*rand.Rand
+	seed int64
+	src  *countingSource
+}
+
+// countingSource counts how many values have been drawn so that the stream
+// 	can be fast forwarded to the same position after loading
+type countingSource struct {
+	src   rand.Source64
+	draws uint64
+}
+
+func (s *countingSource) Int63() int64 {
+	s.draws++
+	return s.src.Int63()
+}
+
+func (s *countingSource) Uint64() uint64 {
+	s.draws++
+	return s.src.Uint64()
+}
+
+func (s *countingSource) Seed(seed int64) {
+	s.draws = 0
+	s.src.Seed(seed)
+}
+
+func CreateRng(seed int64) *Rng {
+
+	src := &countingSource{
+		src: rand.NewSource(seed).(rand.Source64),
+	}
+
+	return &Rng{
+		Rand: rand.New(src),
+		seed: seed,
+		src:  src,
+	}
+}
+
+// restoreRng recreates a stream from its seed, skipping the values that
+// 	had already been drawn
+func restoreRng(seed int64, draws uint64) *Rng {
+
+	r := CreateRng(seed)
+	for r.src.draws < draws {
+		r.src.Int63()
+	}
+	return r
+}
+
+// Seed returns the seed the stream was created with
+func (r *Rng) Seed() int64 {
+	return r.seed
+}
+
+// Draws returns how many values have been drawn from the stream
+func (r *Rng) Draws() uint64 {
+	return r.src.draws
+}
@@ -0,0 +1,571 @@
+package sim
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"image"
+	"io"
+	"os"
+	"strings"
+)
+
+// SaveVersion is the version of the save format written by this build. Bump
+// 	it whenever the format changes and add a Migration from the old version
+const SaveVersion = 8
+
+// Migration upgrades a decoded save from the version it is registered
+// 	against to the next version
+type Migration func(save map[string]interface{}) error
+
+// Migrations are keyed by the version they upgrade from, i.e Migrations[1]
+// 	turns a version 1 save into a version 2 save
+var Migrations = map[int]Migration{
+	// version 2 added the seeded rng. there's no way to know what the old
+	// 	game was seeded with, so start a fresh stream
+	1: func(save map[string]interface{}) error {
+		save["rng"] = map[string]interface{}{
+			"seed":  0,
+			"draws": 0,
+		}
+		return nil
+	},
+	// version 3 turned stocks from a struct into a map keyed by resource
+	2: func(save map[string]interface{}) error {
+		old, _ := save["stocks"].(map[string]interface{})
+		stocks := map[string]interface{}{}
+		for k, v := range old {
+			stocks[strings.ToLower(k)] = v
+		}
+		save["stocks"] = stocks
+		return nil
+	},
+	// version 4 replaced the research flags with the tech tree
+	3: func(save map[string]interface{}) error {
+		old, _ := save["research"].(map[string]interface{})
+		done := map[string]interface{}{}
+		for k, v := range old {
+			if v == true {
+				done[strings.ToLower(k)] = true
+			}
+		}
+		save["research"] = map[string]interface{}{
+			"done":     done,
+			"progress": map[string]interface{}{},
+			"banked":   0,
+		}
+		return nil
+	},
+	// version 5 added the ages between the Neolithic and the Roman Age and
+	// 	dropped the Apocalyptic Age
+	4: func(save map[string]interface{}) error {
+		epochs := []int{EpochNeolithic, EpochRoman, EpochClassical, EpochSteam, EpochModern, EpochTranshuman, EpochTranshuman}
+		old, _ := save["epoch"].(float64)
+		if int(old) < 0 || int(old) >= len(epochs) {
+			return fmt.Errorf("unknown epoch %v", save["epoch"])
+		}
+		save["epoch"] = epochs[int(old)]
+		return nil
+	},
+	// version 6 listed every settlement, not just the first, and replaced
+	// 	citizens' assignments with jobs
+	5: func(save map[string]interface{}) error {
+		world, _ := save["world"].(map[string]interface{})
+		columns, _ := world["squares"].([]interface{})
+		settlements, _ := world["settlements"].([]interface{})
+
+		listed := map[image.Point]bool{}
+		for _, v := range settlements {
+			p, _ := v.(map[string]interface{})
+			x, _ := p["X"].(float64)
+			y, _ := p["Y"].(float64)
+			listed[image.Point{X: int(x), Y: int(y)}] = true
+		}
+
+		// squareAt returns the raw square at x,y, if it's on the map
+		squareAt := func(x, y int) map[string]interface{} {
+			if x < 0 || x >= len(columns) {
+				return nil
+			}
+			column, _ := columns[x].([]interface{})
+			if y < 0 || y >= len(column) {
+				return nil
+			}
+			square, _ := column[y].(map[string]interface{})
+			return square
+		}
+
+		// settlementAt returns the raw settlement on a square, if any
+		settlementAt := func(x, y int) map[string]interface{} {
+			settlement, _ := squareAt(x, y)["settlement"].(map[string]interface{})
+			return settlement
+		}
+
+		for x := range columns {
+			column, _ := columns[x].([]interface{})
+			for y := range column {
+				settlement := settlementAt(x, y)
+				if settlement == nil {
+					continue
+				}
+				if !listed[image.Point{X: x, Y: y}] {
+					settlements = append(settlements, map[string]interface{}{"X": x, "Y": y})
+				}
+
+				citizens, _ := settlement["citizens"].([]interface{})
+				for _, v := range citizens {
+					c, _ := v.(map[string]interface{})
+					if a, ok := c["Assignment"].(map[string]interface{}); ok {
+						ax, _ := a["X"].(float64)
+						ay, _ := a["Y"].(float64)
+						kind := JobGather
+						if target := settlementAt(int(ax), int(ay)); target != nil {
+							if sk, ok := SettlementKinds[fmt.Sprint(target["kind"])]; ok && sk.Produces == RtStudy {
+								kind = JobResearch
+							}
+						}
+						// monoliths are studied, not gathered
+						if rt, ok := ResourceTypes[fmt.Sprint(squareAt(int(ax), int(ay))["resource"])]; ok && rt.Produces == ResResearch {
+							kind = JobResearch
+						}
+						c["Job"] = map[string]interface{}{"kind": kind, "target": a}
+					}
+					delete(c, "Assignment")
+				}
+			}
+		}
+
+		world["settlements"] = settlements
+		return nil
+	},
+	// version 7 made sure every citizen has a unique ID. Saves from before
+	// 	IDs were handed out have them all as 0
+	6: func(save map[string]interface{}) error {
+		world, _ := save["world"].(map[string]interface{})
+		columns, _ := world["squares"].([]interface{})
+
+		citizens := []map[string]interface{}{}
+		var collect func(columns []interface{})
+		collect = func(columns []interface{}) {
+			for _, column := range columns {
+				squares, _ := column.([]interface{})
+				for _, v := range squares {
+					square, _ := v.(map[string]interface{})
+					settlement, _ := square["settlement"].(map[string]interface{})
+					list, _ := settlement["citizens"].([]interface{})
+					for _, c := range list {
+						if citizen, ok := c.(map[string]interface{}); ok {
+							citizens = append(citizens, citizen)
+						}
+					}
+					region, _ := square["region"].([]interface{})
+					collect(region)
+				}
+			}
+		}
+		collect(columns)
+
+		highest := 0
+		for _, c := range citizens {
+			if id, _ := c["ID"].(float64); int(id) > highest {
+				highest = int(id)
+			}
+		}
+
+		// the first citizen with an ID keeps it, the rest get new ones
+		used := map[int]bool{}
+		for _, c := range citizens {
+			id, _ := c["ID"].(float64)
+			if id < 1 || used[int(id)] {
+				highest++
+				id = float64(highest)
+				c["ID"] = id
+			}
+			used[int(id)] = true
+		}
+
+		if next, _ := world["nextCitizenId"].(float64); int(next) <= highest {
+			world["nextCitizenId"] = highest + 1
+		}
+		return nil
+	},
+	// version 8 saved the regions inside world squares, with the workplaces
+	// 	built in them and citizens' region jobs, and settlements' construction
+	// 	queues. Older saves have none. Regions are generated when they're
+	// 	first needed and queues are rebuilt in the order constructions are
+	// 	found
+	7: func(save map[string]interface{}) error {
+		return nil
+	},
+}
+
+// saveFile is the on disk representation of a GameState. Pointers are
+// 	replaced with IDs and coordinates so the world can be rebuilt
+type saveFile struct {
+	Version  int      `json:"version"`
+	Rng      savedRng `json:"rng"`
+	Year     int      `json:"year"`
+	Epoch    int      `json:"epoch"`
+	Stocks   Stocks   `json:"stocks"`
+	Research Research `json:"research"`
+	Stats    Stats    `json:"stats"`
+	Hunger   float64  `json:"hunger"`
+	Outcome  string   `json:"outcome,omitempty"`
+	// Catastrophe is the ID of the hazard that ended the game, if any
+	Catastrophe string     `json:"catastrophe,omitempty"`
+	World       savedWorld `json:"world"`
+}
+
+type savedRng struct {
+	Seed  int64  `json:"seed"`
+	Draws uint64 `json:"draws"`
+}
+
+type savedWorld struct {
+	Squares [][]savedSquare `json:"squares"`
+	// Settlements are the coordinates of the settlements in the world's list
+	Settlements   []image.Point `json:"settlements"`
+	NextCitizenID int           `json:"nextCitizenId"`
+}
+
+type savedSquare struct {
+	Kind       int              `json:"kind"`
+	Height     int              `json:"height"`
+	Liquid     bool             `json:"liquid,omitempty"`
+	Resource   string           `json:"resource,omitempty"`
+	Remaining  float64          `json:"remaining,omitempty"`
+	Settlement *savedSettlement `json:"settlement,omitempty"`
+	// Region is nil if it hasn't been generated yet
+	Region [][]savedSquare `json:"region,omitempty"`
+}
+
+type savedSettlement struct {
+	Kind      string         `json:"kind"`
+	Progress  float64        `json:"progress"`
+	Completed bool           `json:"completed"`
+	Citizens  []Citizen      `json:"citizens"`
+	Queue     []Construction `json:"queue,omitempty"`
+}
+
+// Save writes the game state as JSON, wrapped in gzip if compress is true
+func (g *GameState) Save(w io.Writer, compress bool) error {
+
+	if compress {
+		gz := gzip.NewWriter(w)
+		if err := g.Save(gz, false); err != nil {
+			return err
+		}
+		return gz.Close()
+	}
+
+	enc := json.NewEncoder(w)
+	return enc.Encode(g.toSaveFile())
+}
+
+// SaveFile saves the game state to path. Paths ending in .gz are compressed
+func (g *GameState) SaveFile(path string) error {
+
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+
+	if err := g.Save(f, strings.HasSuffix(path, ".gz")); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// Load reads a game state written by Save, compressed or not, migrating it
+// 	to the current version if it is older
+func Load(r io.Reader) (*GameState, error) {
+
+	br := bufio.NewReader(r)
+
+	// gzip streams always start with these two bytes, JSON never does
+	magic, err := br.Peek(2)
+	if err == nil && magic[0] == 0x1f && magic[1] == 0x8b {
+		gz, err := gzip.NewReader(br)
+		if err != nil {
+			return nil, err
+		}
+		defer gz.Close()
+		return Load(gz)
+	}
+
+	raw := make(map[string]interface{})
+	if err := json.NewDecoder(br).Decode(&raw); err != nil {
+		return nil, fmt.Errorf("could not read save: %w", err)
+	}
+
+	if err := migrate(raw); err != nil {
+		return nil, err
+	}
+
+	// round trip through JSON again now that the layout is current
+	data, err := json.Marshal(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	var save saveFile
+	if err := json.Unmarshal(data, &save); err != nil {
+		return nil, fmt.Errorf("could not read save: %w", err)
+	}
+
+	return save.toGameState()
+}
+
+// LoadFile loads a game state from path
+func LoadFile(path string) (*GameState, error) {
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return Load(f)
+}
+
+func migrate(save map[string]interface{}) error {
+
+	v, ok := save["version"].(float64)
+	if !ok {
+		return fmt.Errorf("save has no version")
+	}
+
+	version := int(v)
+	if version > SaveVersion {
+		return fmt.Errorf("save version %d is newer than supported version %d", version, SaveVersion)
+	}
+
+	for ; version < SaveVersion; version++ {
+		m, ok := Migrations[version]
+		if !ok {
+			return fmt.Errorf("no migration from save version %d", version)
+		}
+		if err := m(save); err != nil {
+			return fmt.Errorf("migrating save from version %d: %w", version, err)
+		}
+		save["version"] = float64(version + 1)
+	}
+
+	return nil
+}
+
+func (g *GameState) toSaveFile() *saveFile {
+
+	w := g.World
+
+	save := &saveFile{
+		Version: SaveVersion,
+		Rng: savedRng{
+			Seed:  g.Rng.Seed(),
+			Draws: g.Rng.Draws(),
+		},
+		Year:        g.Year,
+		Epoch:       g.Epoch,
+		Stocks:      g.Stocks,
+		Research:    g.Research,
+		Stats:       g.Stats,
+		Hunger:      g.Hunger,
+		Outcome:     g.Outcome,
+		Catastrophe: g.Catastrophe,
+		World: savedWorld{
+			Squares:       make([][]savedSquare, len(w.Squares)),
+			Settlements:   []image.Point{},
+			NextCitizenID: w.NextCitizenID,
+		},
+	}
+
+	for x := 0; x < len(w.Squares); x++ {
+		save.World.Squares[x] = make([]savedSquare, len(w.Squares[x]))
+		for y := 0; y < len(w.Squares[x]); y++ {
+			save.World.Squares[x][y] = saveSquare(&w.Squares[x][y])
+		}
+	}
+
+	for _, s := range w.Settlements {
+		save.World.Settlements = append(save.World.Settlements, image.Point{X: s.WorldX, Y: s.WorldY})
+	}
+
+	return save
+}
+
+func (save *saveFile) toGameState() (*GameState, error) {
+
+	if len(save.World.Squares) == 0 || len(save.World.Squares[0]) == 0 {
+		return nil, fmt.Errorf("save has no world")
+	}
+
+	w := &World{
+		Squares:       make([][]Square, len(save.World.Squares)),
+		Settlements:   []*Settlement{},
+		NextCitizenID: save.World.NextCitizenID,
+	}
+
+	for x := 0; x < len(save.World.Squares); x++ {
+		w.Squares[x] = make([]Square, len(save.World.Squares[x]))
+		for y := 0; y < len(save.World.Squares[x]); y++ {
+			square, err := loadSquare(save.World.Squares[x][y], x, y)
+			if err != nil {
+				return nil, err
+			}
+			w.Squares[x][y] = square
+		}
+	}
+
+	for _, p := range save.World.Settlements {
+		if !w.TileIsInRange(p.X, p.Y) || w.Squares[p.X][p.Y].Settlement == nil {
+			return nil, fmt.Errorf("no settlement at %d,%d", p.X, p.Y)
+		}
+		w.Settlements = append(w.Settlements, w.Squares[p.X][p.Y].Settlement)
+	}
+
+	if save.Epoch < 0 || save.Epoch >= len(Epochs) {
+		return nil, fmt.Errorf("unknown epoch %d", save.Epoch)
+	}
+
+	research := CreateResearch()
+	for k, v := range save.Research.Done {
+		if _, ok := Techs[k]; !ok {
+			return nil, fmt.Errorf("unknown tech '%s'", k)
+		}
+		research.Done[k] = v
+	}
+	for k, v := range save.Research.Progress {
+		if _, ok := Techs[k]; !ok {
+			return nil, fmt.Errorf("unknown tech '%s'", k)
+		}
+		research.Progress[k] = v
+	}
+	if _, ok := Techs[save.Research.Current]; save.Research.Current != "" && !ok {
+		return nil, fmt.Errorf("unknown tech '%s'", save.Research.Current)
+	}
+	research.Current = save.Research.Current
+	research.Banked = save.Research.Banked
+
+	stocks := CreateStocks()
+	for k, v := range save.Stocks {
+		if _, ok := Resources[k]; !ok {
+			return nil, fmt.Errorf("unknown resource '%s'", k)
+		}
+		stocks[k] = v
+	}
+
+	return &GameState{
+		Rng:         restoreRng(save.Rng.Seed, save.Rng.Draws),
+		World:       w,
+		Stocks:      stocks,
+		Research:    research,
+		Year:        save.Year,
+		Epoch:       save.Epoch,
+		Stats:       save.Stats,
+		Hunger:      save.Hunger,
+		Outcome:     save.Outcome,
+		Catastrophe: save.Catastrophe,
+	}, nil
+}
+
+// saveSquare converts a square, and its region if it has one, for saving
+func saveSquare(square *Square) savedSquare {
+
+	ss := savedSquare{
+		Kind:   square.Kind,
+		Height: square.Height,
+		Liquid: square.Liquid,
+	}
+	if square.Resource != nil {
+		ss.Resource = square.Resource.ID
+		ss.Remaining = square.Remaining
+	}
+	if s := square.Settlement; s != nil {
+		ss.Settlement = &savedSettlement{
+			Kind:      s.Kind.ID,
+			Progress:  s.Progress,
+			Completed: s.Completed,
+			Citizens:  s.Citizens,
+			Queue:     s.Queue,
+		}
+	}
+	if r := square.Region; r != nil {
+		ss.Region = make([][]savedSquare, len(r.Squares))
+		for x := range r.Squares {
+			ss.Region[x] = make([]savedSquare, len(r.Squares[x]))
+			for y := range r.Squares[x] {
+				ss.Region[x][y] = saveSquare(&r.Squares[x][y])
+			}
+		}
+	}
+	return ss
+}
+
+// loadSquare converts a saved square back. Settlements in its region belong
+// 	to the world square at x,y
+func loadSquare(ss savedSquare, x, y int) (Square, error) {
+
+	square := CreateSquare()
+	square.Kind = ss.Kind
+	square.Height = ss.Height
+	square.Liquid = ss.Liquid
+
+	if ss.Resource != "" {
+		rt, ok := ResourceTypes[ss.Resource]
+		if !ok {
+			return square, fmt.Errorf("unknown resource type '%s' at %d,%d", ss.Resource, x, y)
+		}
+		square.Resource = rt
+		square.Remaining = ss.Remaining
+	}
+
+	if ss.Settlement != nil {
+		sk, ok := SettlementKinds[ss.Settlement.Kind]
+		if !ok {
+			return square, fmt.Errorf("unknown settlement kind '%s' at %d,%d", ss.Settlement.Kind, x, y)
+		}
+		citizens := ss.Settlement.Citizens
+		if citizens == nil {
+			citizens = []Citizen{}
+		}
+		for i := range citizens {
+			if citizens[i].Proficiencies == nil {
+				citizens[i].Proficiencies = CreateProficiencies()
+			}
+		}
+		square.Settlement = &Settlement{
+			WorldX:    x,
+			WorldY:    y,
+			Kind:      sk,
+			Progress:  ss.Settlement.Progress,
+			Completed: ss.Settlement.Completed,
+			Citizens:  citizens,
+			Queue:     ss.Settlement.Queue,
+		}
+	}
+
+	if ss.Region != nil {
+		if len(ss.Region) != RegionSize {
+			return square, fmt.Errorf("region at %d,%d is the wrong size", x, y)
+		}
+		r := &Region{Squares: make([][]Square, RegionSize)}
+		for ix := range ss.Region {
+			if len(ss.Region[ix]) != RegionSize {
+				return square, fmt.Errorf("region at %d,%d is the wrong size", x, y)
+			}
+			r.Squares[ix] = make([]Square, RegionSize)
+			for iy := range ss.Region[ix] {
+				inner, err := loadSquare(ss.Region[ix][iy], x, y)
+				if err != nil {
+					return square, err
+				}
+				r.Squares[ix][iy] = inner
+			}
+		}
+		square.Region = r
+	}
+
+	return square, nil
+}
@@ -0,0 +1,222 @@
+package sim
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestSaveLoadRoundTrip(t *testing.T) {
+
+	tests := []struct {
+		seed     int64
+		turns    int
+		compress bool
+	}{
+		{seed: 1, turns: 0, compress: false},
+		{seed: 2, turns: 5, compress: true},
+		{seed: 3, turns: 20, compress: false},
+		{seed: 4, turns: 20, compress: true},
+	}
+
+	for _, tt := range tests {
+		g := createTestGame(t, tt.seed)
+		playTurns(g, tt.turns)
+		// regions are saved once they've been generated
+		s := g.World.Settlements[0]
+		g.Region(s.WorldX, s.WorldY)
+
+		var buf bytes.Buffer
+		if err := g.Save(&buf, tt.compress); err != nil {
+			t.Fatalf("seed %d: saving: %v", tt.seed, err)
+		}
+		loaded, err := Load(&buf)
+		if err != nil {
+			t.Fatalf("seed %d: loading: %v", tt.seed, err)
+		}
+
+		if !bytes.Equal(saveBytes(t, g), saveBytes(t, loaded)) {
+			t.Errorf("seed %d: loaded game saves differently", tt.seed)
+		}
+
+		// the rng carries on where it left off, so the games stay in step
+		playTurns(g, 5)
+		playTurns(loaded, 5)
+		if !bytes.Equal(saveBytes(t, g), saveBytes(t, loaded)) {
+			t.Errorf("seed %d: loaded game played differently", tt.seed)
+		}
+	}
+}
+
+// saveV1 is a version 1 save, from before any migration, with a village and
+// 	a farm on a 2x2 world. One villager works the woods, one researches
+// 	in the village, one studies a monolith and one is idle
+const saveV1 = `{
+	"version": 1,
+	"year": 5,
+	"epoch": 1,
+	"stocks": {"FOOD": 3, "WOOD": 2},
+	"research": {"FARMING": true, "WRITING": false},
+	"world": {
+		"squares": [
+			[
+				{"kind": 1, "height": 0, "settlement": {"kind": "VILLAGE", "progress": 1, "completed": true, "citizens": [
+					{"Name": "Alice", "Age": 20, "Gender": "female", "Genetics": 100, "Assignment": {"X": 0, "Y": 1}},
+					{"Name": "Bob", "Age": 20, "Gender": "male", "Genetics": 100, "Assignment": {"X": 0, "Y": 0}},
+					{"Name": "Carol", "Age": 2, "Gender": "female", "Genetics": 100},
+					{"Name": "Dave", "Age": 30, "Gender": "male", "Genetics": 100, "Assignment": {"X": 1, "Y": 0}}
+				]}},
+				{"kind": 1, "height": 0, "resource": "forest"}
+			],
+			[
+				{"kind": 1, "height": 0, "resource": "monolith"},
+				{"kind": 1, "height": 0, "settlement": {"kind": "FARM", "progress": 0.5, "completed": false, "citizens": []}}
+			]
+		],
+		"settlements": [{"X": 0, "Y": 0}]
+	}
+}`
+
+func TestLoadMigratesEveryVersion(t *testing.T) {
+
+	for v := 1; v < SaveVersion; v++ {
+		if _, ok := Migrations[v]; !ok {
+			t.Errorf("no migration from version %d", v)
+		}
+	}
+
+	g, err := Load(strings.NewReader(saveV1))
+	if err != nil {
+		t.Fatalf("loading version 1 save: %v", err)
+	}
+
+	if g.Stocks[ResFood] != 3 || g.Stocks[ResWood] != 2 {
+		t.Errorf("stocks are %v, want 3 food and 2 wood", g.Stocks)
+	}
+	if !g.Research.Has(TechFarming) || g.Research.Has(TechWriting) {
+		t.Errorf("research is %v, want only farming", g.Research.Done)
+	}
+	// the old second age was the roman age
+	if g.Epoch != EpochRoman {
+		t.Errorf("epoch is %d, want %d", g.Epoch, EpochRoman)
+	}
+	if len(g.World.Settlements) != 2 {
+		t.Fatalf("%d settlements are listed, want 2", len(g.World.Settlements))
+	}
+
+	// citizens from before IDs get unique ones, and new ones don't clash
+	ids := map[int]bool{}
+	for _, s := range g.World.Settlements {
+		for _, c := range s.Citizens {
+			if c.ID < 1 || ids[c.ID] {
+				t.Errorf("%s has ID %d, which isn't unique", c.Name, c.ID)
+			}
+			ids[c.ID] = true
+		}
+	}
+	if id := g.World.CreateCitizenID(); ids[id] {
+		t.Errorf("the next citizen ID %d is already taken", id)
+	}
+
+	village := g.World.Settlements[0]
+	jobs := map[string]JobKind{"Alice": JobGather, "Bob": JobResearch, "Carol": JobIdle, "Dave": JobResearch}
+	for _, c := range village.Citizens {
+		if want := jobs[c.Name]; c.JobKind() != want {
+			t.Errorf("%s's job is %s, want %s", c.Name, c.JobKind(), want)
+		}
+	}
+
+	// and it saves as the current version
+	loaded, err := Load(bytes.NewReader(saveBytes(t, g)))
+	if err != nil {
+		t.Fatalf("reloading migrated save: %v", err)
+	}
+	if !bytes.Equal(saveBytes(t, g), saveBytes(t, loaded)) {
+		t.Errorf("migrated game saves differently after reloading")
+	}
+}
+
+func TestCitizenIDMigration(t *testing.T) {
+
+	tests := []struct {
+		name string
+		// ids are the citizens' IDs before migrating, 0 for none
+		ids  []int
+		next int
+	}{
+		{name: "none have IDs", ids: []int{0, 0, 0}, next: 0},
+		{name: "all have IDs", ids: []int{1, 2, 3}, next: 4},
+		{name: "some have IDs", ids: []int{0, 5, 0}, next: 6},
+		{name: "duplicate IDs", ids: []int{2, 2, 0}, next: 1},
+	}
+
+	for _, tt := range tests {
+		citizens := []interface{}{}
+		for _, id := range tt.ids {
+			citizens = append(citizens, map[string]interface{}{"ID": float64(id)})
+		}
+		save := map[string]interface{}{
+			"world": map[string]interface{}{
+				"nextCitizenId": float64(tt.next),
+				"squares": []interface{}{[]interface{}{
+					map[string]interface{}{"settlement": map[string]interface{}{"citizens": citizens}},
+				}},
+			},
+		}
+
+		if err := Migrations[6](save); err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+
+		highest := 0
+		seen := map[int]bool{}
+		for i, v := range citizens {
+			id := int(v.(map[string]interface{})["ID"].(float64))
+			if id < 1 || seen[id] {
+				t.Errorf("%s: citizen %d has ID %d, which isn't unique", tt.name, i, id)
+			}
+			if tt.ids[i] > 0 && !seen[tt.ids[i]] && id != tt.ids[i] {
+				t.Errorf("%s: citizen %d's ID changed from %d to %d", tt.name, i, tt.ids[i], id)
+			}
+			seen[id] = true
+			if id > highest {
+				highest = id
+			}
+		}
+
+		world := save["world"].(map[string]interface{})
+		if next := toInt(world["nextCitizenId"]); next <= highest {
+			t.Errorf("%s: next citizen ID is %d, want more than %d", tt.name, next, highest)
+		}
+	}
+}
+
+// toInt reads a number from decoded JSON, or from a migration
+func toInt(v interface{}) int {
+	switch n := v.(type) {
+	case float64:
+		return int(n)
+	case int:
+		return n
+	}
+	return 0
+}
+
+func TestLoadRejectsBadSaves(t *testing.T) {
+
+	tests := []struct {
+		name string
+		save string
+	}{
+		{name: "not json", save: "hello"},
+		{name: "no version", save: `{"year": 1}`},
+		{name: "newer version", save: `{"version": 999}`},
+		{name: "no world", save: `{"version": 8, "world": {"squares": []}}`},
+	}
+
+	for _, tt := range tests {
+		if _, err := Load(strings.NewReader(tt.save)); err == nil {
+			t.Errorf("%s: loaded without an error", tt.name)
+		}
+	}
+}
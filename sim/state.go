@@ -0,0 +1,199 @@
+// Package sim contains the rules of the game and nothing to do with drawing
+// 	it, so that a game can be run and tested without a display
+package sim
+
+import "fmt"
+
+// GameState is everything needed to simulate a game
+type GameState struct {
+	// Rng is the only source of randomness the simulation may use
+	Rng      *Rng
+	World    *World
+	Stocks   Stocks
+	Research Research
+	Year     int
+	Epoch    int
+	Stats    Stats
+	// Hunger is the fraction of the food needed this year that there wasn't
+	// 	enough of, 0-1
+	Hunger float64
+	// Outcome is how the game ended, if it has, i.e OutcomeVictory
+	Outcome string
+	// Catastrophe is the ID of the hazard that ended the world, if one did
+	Catastrophe string
+}
+
+// Stats are running totals kept over the whole game
+type Stats struct {
+	Deaths         int
+	Births         int
+	PeakPopulation int
+	// Spent is how much of each resource has been used up
+	Spent Stocks
+}
+
+// Spend records amount of resource as used up
+func (s *Stats) Spend(resource string, amount float64) {
+	if s.Spent == nil {
+		s.Spent = Stocks{}
+	}
+	s.Spent[resource] += amount
+}
+
+// TurnReport is what happened during a turn, for the UI to present
+type TurnReport struct {
+	Year     int
+	Messages []string
+	Deaths   []Death
+	Births   []Birth
+}
+
+func (r *TurnReport) AddMessage(format string, a ...interface{}) {
+	r.Messages = append(r.Messages, fmt.Sprintf(format, a...))
+}
+
+// CreateGameState starts a new game. The same seed and options will always
+// 	produce the same game
+func CreateGameState(seed int64, opts WorldOptions) (*GameState, error) {
+
+	rng := CreateRng(seed)
+	stocks := CreateStocks()
+	stocks[ResFood] = StartingFood
+
+	world, err := CreateWorld(rng, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	g := &GameState{
+		Rng:      rng,
+		World:    world,
+		Stocks:   stocks,
+		Research: CreateResearch(),
+		Year:     StartYear,
+		Epoch:    EpochNeolithic,
+	}
+	g.Stats.PeakPopulation = g.Population()
+	return g, nil
+}
+
+// Population is how many citizens there are
+func (g *GameState) Population() int {
+	total := 0
+	for _, s := range g.World.Settlements {
+		total += len(s.Citizens)
+	}
+	return total
+}
+
+// AdvanceTurn ends the current year and runs every phase of the turn
+func (g *GameState) AdvanceTurn() *TurnReport {
+
+	// nothing happens once the game is over
+	if g.Over() {
+		return &TurnReport{Year: g.Year}
+	}
+
+	g.Year++
+	report := &TurnReport{
+		Year: g.Year,
+	}
+
+	// negative factors first to minimise cheesing
+	g.ProcessAnnihilation(report)
+	if g.Over() {
+		return report
+	}
+	g.ProcessFeeding(report)
+	g.ProcessMortality(report)
+	g.ProcessHousing(report)
+	g.ProcessHealthcare(report)
+	g.ProcessExtinction(report)
+	if g.Over() {
+		return report
+	}
+	g.ProcessBirths(report)
+	g.ProcessConstruction(report)
+	g.ProcessEducation(report)
+	g.ProcessMoves(report)
+	g.ProcessResearch(report)
+	g.ProcessEpoch(report)
+	g.ProcessMonoliths(report)
+
+	if p := g.Population(); p > g.Stats.PeakPopulation {
+		g.Stats.PeakPopulation = p
+	}
+
+	return report
+}
+
+// ProcessConstruction has citizens do their jobs. Builders put their effort
+// 	into their construction and idle citizens spread theirs over the
+// 	constructions next to their settlement
+func (g *GameState) ProcessConstruction(report *TurnReport) {
+
+	w := g.World
+
+	// the skill each citizen practised this year, by ID
+	practised := map[int]string{}
+
+	for _, s := range w.Settlements {
+		for i := 0; i < len(s.Citizens); i++ {
+			c := &s.Citizens[i]
+
+			// children can't work, the working age may have risen since
+			// 	they were assigned, and what they were doing may be gone
+			g.CheckJob(c, s)
+
+			switch c.JobKind() {
+			case JobGather, JobResearch:
+				practised[c.ID] = g.JobSkill(*c.Job)
+				c.Work(g, report)
+			case JobTeach, JobDoctor:
+				practised[c.ID] = g.JobSkill(*c.Job)
+			case JobBuild:
+				practised[c.ID] = SkillBuilding
+			}
+		}
+	}
+
+	// builders and idle citizens work on constructions
+	building := g.ConstructionForecast()
+
+	for _, s := range w.Settlements {
+		_, used := g.planConstruction(s)
+		for _, id := range used {
+			practised[id] = SkillBuilding
+		}
+		for i := range s.Citizens {
+			s.Citizens[i].Train(practised[s.Citizens[i].ID])
+		}
+	}
+
+	// in list order so that it's the same every time
+	for _, s := range w.Settlements {
+		if effort, ok := building[s]; ok && s.ApplyEffort(effort) {
+			report.AddMessage("Construction completed on '%s'", s.Kind.Name)
+		}
+	}
+
+	// then the workplaces in each settlement's region
+	for _, s := range w.Settlements {
+		r := w.Squares[s.WorldX][s.WorldY].Region
+		if r == nil {
+			continue
+		}
+		for x := range r.Squares {
+			for y := range r.Squares[x] {
+				inner := r.Squares[x][y].Settlement
+				if effort, ok := building[inner]; ok && inner.ApplyEffort(effort) {
+					report.AddMessage("Construction completed on '%s' in the region at %d,%d", inner.Kind.Name, s.WorldX, s.WorldY)
+				}
+			}
+		}
+	}
+
+	for _, s := range w.Settlements {
+		g.SyncQueue(s)
+	}
+}
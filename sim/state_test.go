@@ -0,0 +1,129 @@
+package sim
+
+import (
+	"bytes"
+	"image"
+	"testing"
+)
+
+// createTestGame starts a game on the default world with the given seed
+func createTestGame(t *testing.T, seed int64) *GameState {
+	t.Helper()
+
+	g, err := CreateGameState(seed, DefaultWorldOptions())
+	if err != nil {
+		t.Fatalf("creating game with seed %d: %v", seed, err)
+	}
+	return g
+}
+
+// createFlatGame starts a game on a small world of empty grass, with the
+// 	spawn village in the middle at 2,2, so tests can lay out the squares
+// 	they need
+func createFlatGame(t *testing.T) (*GameState, *Settlement) {
+	t.Helper()
+
+	rng := CreateRng(1)
+	squares := make([][]Square, 5)
+	for x := range squares {
+		squares[x] = make([]Square, 5)
+		for y := range squares[x] {
+			squares[x][y] = CreateGrass()
+		}
+	}
+	w := &World{Squares: squares}
+	w.CreateSettlements(rng, image.Point{X: 2, Y: 2})
+
+	g := &GameState{
+		Rng:      rng,
+		World:    w,
+		Stocks:   CreateStocks(),
+		Research: CreateResearch(),
+		Year:     StartYear,
+		Epoch:    EpochNeolithic,
+	}
+	return g, w.Settlements[0]
+}
+
+// build puts a settlement of the given kind at x,y, completed or not
+func build(g *GameState, kind string, x, y int, completed bool) *Settlement {
+	s := g.World.CreateSettlement(SettlementKinds[kind], x, y)
+	s.Completed = completed
+	g.World.Squares[x][y].Settlement = s
+	return s
+}
+
+// emptyNeighbour returns a square next to the settlement that can be built on
+func emptyNeighbour(t *testing.T, g *GameState, s *Settlement) image.Point {
+	t.Helper()
+
+	for _, n := range g.World.Neighbours(s.WorldX, s.WorldY) {
+		if square := &g.World.Squares[n.X][n.Y]; square.Kind == TGrass && square.IsEmpty() {
+			return n
+		}
+	}
+	t.Fatalf("nowhere to build next to %d,%d", s.WorldX, s.WorldY)
+	return image.Point{}
+}
+
+// saveBytes is the uncompressed save of the game
+func saveBytes(t *testing.T, g *GameState) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	if err := g.Save(&buf, false); err != nil {
+		t.Fatalf("saving: %v", err)
+	}
+	return buf.Bytes()
+}
+
+// playTurns advances the game, topping up food so the game doesn't end
+// 	early from starvation
+func playTurns(g *GameState, turns int) {
+	for i := 0; i < turns; i++ {
+		g.Stocks[ResFood] += 10
+		g.AdvanceTurn()
+	}
+}
+
+func TestAdvanceTurnIsDeterministic(t *testing.T) {
+
+	tests := []struct {
+		seed  int64
+		turns int
+	}{
+		{seed: 1, turns: 1},
+		{seed: 2, turns: 10},
+		{seed: 3, turns: 30},
+		{seed: 12345, turns: 50},
+	}
+
+	for _, tt := range tests {
+		a := createTestGame(t, tt.seed)
+		b := createTestGame(t, tt.seed)
+		playTurns(a, tt.turns)
+		playTurns(b, tt.turns)
+
+		if !bytes.Equal(saveBytes(t, a), saveBytes(t, b)) {
+			t.Errorf("seed %d: games differ after %d turns", tt.seed, tt.turns)
+		}
+		if a.Year != StartYear+tt.turns && !a.Over() {
+			t.Errorf("seed %d: year is %d after %d turns, want %d", tt.seed, a.Year, tt.turns, StartYear+tt.turns)
+		}
+	}
+}
+
+func TestAdvanceTurnDoesNothingOnceOver(t *testing.T) {
+
+	g := createTestGame(t, 1)
+	g.Outcome = OutcomeVictory
+	before := saveBytes(t, g)
+
+	report := g.AdvanceTurn()
+	if report.Year != g.Year {
+		t.Errorf("report year is %d, want %d", report.Year, g.Year)
+	}
+	if !bytes.Equal(before, saveBytes(t, g)) {
+		t.Errorf("game changed after it was over")
+	}
+}
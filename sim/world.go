@@ -0,0 +1,349 @@
+package sim
+
+import (
+	"fmt"
+	"image"
+)
+
+type Square struct {
+	Kind       int
+	Height     int
+	Liquid     bool
+	Settlement *Settlement
+	Resource   *ResourceType
+	// Remaining is how much of a resource with a deposit is left
+	Remaining float64
+	// Region is the grid inside the square, nil until it's first needed.
+	// 	Squares inside a region don't have regions of their own
+	Region *Region
+}
+
+// HasCompletedSettlement returns true if this square has a settlement that
+// 	has completed construction
+func (s *Square) HasCompletedSettlement() bool {
+	if s.Settlement == nil {
+		return false
+	}
+
+	return s.Settlement.Completed
+}
+
+func (s *Square) HasResource() bool {
+	return s.Resource != nil
+}
+
+func (s *Square) IsEmpty() bool {
+	return s.Settlement == nil && s.Resource == nil
+}
+
+// PlaceResource puts a resource on the square, with a full deposit
+func (s *Square) PlaceResource(rt *ResourceType) {
+	s.Resource = rt
+	s.Remaining = rt.Deposit
+}
+
+// Deplete takes amount from the square's deposit, if its resource has one,
+// 	and returns how much could actually be taken. The resource is removed
+// 	once it's all gone
+func (s *Square) Deplete(amount float64) float64 {
+
+	if s.Resource == nil || s.Resource.Deposit <= 0 {
+		return amount
+	}
+
+	if amount > s.Remaining {
+		amount = s.Remaining
+	}
+	s.Remaining -= amount
+	// allow for floating point error so that deposits don't linger on
+	// 	with nothing in them
+	if s.Remaining <= 1e-9 {
+		s.Resource = nil
+		s.Remaining = 0
+	}
+	return amount
+}
+
+// Workable returns the resource type citizens can work on this square, either
+// 	a natural resource or one produced by a completed building, or nil
+func (s *Square) Workable() *ResourceType {
+	if s.Resource != nil {
+		return s.Resource
+	}
+	if s.HasCompletedSettlement() && s.Settlement.Kind.Produces != "" {
+		return ResourceTypes[s.Settlement.Kind.Produces]
+	}
+	return nil
+}
+
+type Settlement struct {
+	// TODO CONSIDER whether or not this is duplication
+	WorldX, WorldY int
+	Kind           *SettlementKind
+	Progress       float64
+	Completed      bool
+	Citizens       []Citizen
+	// Queue is the order the settlement's idle citizens build neighbouring
+	// 	constructions in, see ConstructionQueue
+	Queue []Construction
+}
+
+// ApplyEffort progresses construction. Returns true if this effort completed
+// 	the settlement
+func (s *Settlement) ApplyEffort(effort float64) bool {
+
+	// TODO error if already completed
+	if !s.Completed {
+		s.Progress += effort
+		if s.Progress >= 1 {
+			s.Completed = true
+			return true
+		}
+	}
+	return false
+}
+
+type World struct {
+	Squares     [][]Square
+	Settlements []*Settlement
+	// NextCitizenID is the ID the next citizen to be born will get
+	NextCitizenID int
+}
+
+// CreateCitizenID hands out a unique citizen ID. IDs start at 1 so that 0
+// 	can mean unknown
+func (w *World) CreateCitizenID() int {
+	if w.NextCitizenID < 1 {
+		w.NextCitizenID = 1
+	}
+	id := w.NextCitizenID
+	w.NextCitizenID++
+	return id
+}
+
+func CreateSquare() Square {
+	return Square{}
+}
+
+// CreateWorld generates a world and places the spawn settlement on it
+func CreateWorld(rng *Rng, opts WorldOptions) (*World, error) {
+
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+
+	// some seeds make worlds with no land worth settling. try again
+	for i := 0; i < maxWorldGenAttempts; i++ {
+
+		squares := GenerateWorldTiles(rng, opts)
+		spawn, ok := FindSpawnPoint(squares)
+		if !ok {
+			continue
+		}
+
+		w := &World{
+			Squares: squares,
+		}
+		w.CreateSettlements(rng, spawn)
+
+		return w, nil
+	}
+
+	return nil, fmt.Errorf("could not generate a world with anywhere to spawn after %d attempts", maxWorldGenAttempts)
+}
+
+// Width is the size of the world along the x axis
+func (w *World) Width() int {
+	return len(w.Squares)
+}
+
+// Height is the size of the world along the y axis
+func (w *World) Height() int {
+	if len(w.Squares) == 0 {
+		return 0
+	}
+	return len(w.Squares[0])
+}
+
+// TileIsInRange returns true if coordinates are valid map coordinates
+func (w *World) TileIsInRange(x, y int) bool {
+
+	return image.Point{X: x, Y: y}.In(
+		image.Rectangle{
+			Min: image.Point{
+				X: 0, Y: 0,
+			},
+			Max: image.Point{
+				X: w.Width(), Y: w.Height(),
+			},
+		})
+}
+
+// Neighbours returns the coordinates of the squares north, east, south and
+// 	west of x,y that are on the map
+func (w *World) Neighbours(x, y int) []image.Point {
+
+	neighbours := []image.Point{}
+	for _, p := range []image.Point{{X: x - 1, Y: y}, {X: x, Y: y + 1}, {X: x + 1, Y: y}, {X: x, Y: y - 1}} {
+		if w.TileIsInRange(p.X, p.Y) {
+			neighbours = append(neighbours, p)
+		}
+	}
+	return neighbours
+}
+
+// CanInteractWithTile returns true if this or a neighbouring tile has a
+// 	completed settlement
+func (w *World) CanInteractWithTile(x, y int) bool {
+
+	if !w.TileIsInRange(x, y) {
+		return false
+	}
+
+	if w.Squares[x][y].HasCompletedSettlement() {
+		return true
+	}
+
+	for _, n := range w.Neighbours(x, y) {
+		if w.Squares[n.X][n.Y].HasCompletedSettlement() {
+			return true
+		}
+	}
+	return false
+}
+
+// CreateSettlement creates a settlement, adds it to the world's settlement
+// 	list and returns it so it can be added to the world grid location by
+// 	the calling code
+func (w *World) CreateSettlement(kind *SettlementKind, worldX, worldY int) *Settlement {
+
+	s := &Settlement{
+		WorldX:    worldX,
+		WorldY:    worldY,
+		Kind:      kind,
+		Completed: false,
+		Progress:  0,
+		Citizens:  []Citizen{},
+	}
+
+	w.Settlements = append(w.Settlements, s)
+	return s
+}
+
+// RemoveSettlement takes the settlement off the map and out of the
+// 	settlements list
+func (w *World) RemoveSettlement(s *Settlement) {
+
+	if w.Squares[s.WorldX][s.WorldY].Settlement == s {
+		w.Squares[s.WorldX][s.WorldY].Settlement = nil
+	}
+	for i, other := range w.Settlements {
+		if other == s {
+			w.Settlements = append(w.Settlements[:i], w.Settlements[i+1:]...)
+			return
+		}
+	}
+}
+
+// CreateSpawnSettlement creates the starting village, partly filled with
+// 	young adults split evenly between genders so there can be births
+func (w *World) CreateSpawnSettlement(rng *Rng, worldX, worldY int) *Settlement {
+
+	sk := SettlementKinds[SkVillage]
+	c := []Citizen{}
+
+	for i := 0; i < int(float64(sk.Popcap)*clamp(SpawnOccupancy, 0, 1)); i++ {
+
+		var gender string
+		var name string
+
+		if i%2 == 0 {
+			gender = "female"
+			// right exclusive, neat
+			name = FirstNamesFemale[rng.Intn(len(FirstNamesFemale))]
+		} else {
+			gender = "male"
+			name = FirstNamesMale[rng.Intn(len(FirstNamesMale))]
+		}
+
+		c = append(c, Citizen{
+			ID:            w.CreateCitizenID(),
+			Name:          name,
+			Gender:        gender,
+			Genetics:      100,
+			Age:           18,
+			Proficiencies: CreateProficiencies(),
+		})
+	}
+
+	return &Settlement{
+		WorldX:    worldX,
+		WorldY:    worldY,
+		Kind:      sk,
+		Completed: true,
+		Citizens:  c,
+	}
+}
+
+func (w *World) CreateSettlements(rng *Rng, spawn image.Point) {
+
+	list := []*Settlement{}
+
+	s := w.CreateSpawnSettlement(rng, spawn.X, spawn.Y)
+	list = append(list, s)
+
+	w.Squares[spawn.X][spawn.Y].Settlement = s
+	w.Settlements = list
+}
+
+// FindSettlements returns every settlement of the given kind on the map or
+// 	in a region, built or not. Regions that haven't been generated yet have
+// 	nothing built in them, so they're skipped
+func (w *World) FindSettlements(kind string) []*Settlement {
+
+	settlements := []*Settlement{}
+	for x := 0; x < w.Width(); x++ {
+		for y := 0; y < w.Height(); y++ {
+			s := w.Squares[x][y].Settlement
+			if s != nil && s.Kind.ID == kind {
+				settlements = append(settlements, s)
+			}
+			if r := w.Squares[x][y].Region; r != nil {
+				for _, column := range r.Squares {
+					for _, inner := range column {
+						if inner.Settlement != nil && inner.Settlement.Kind.ID == kind {
+							settlements = append(settlements, inner.Settlement)
+						}
+					}
+				}
+			}
+		}
+	}
+	return settlements
+}
+
+func (w *World) GetAdjacentSettlements(x, y int) []*Settlement {
+
+	settlements := []*Settlement{}
+
+	for _, n := range w.Neighbours(x, y) {
+		if w.Squares[n.X][n.Y].Settlement != nil {
+			settlements = append(settlements, w.Squares[n.X][n.Y].Settlement)
+		}
+	}
+
+	return settlements
+}
+
+func (w *World) GetAdjacentUncompletedSettlements(x, y int) []*Settlement {
+
+	settlements := []*Settlement{}
+
+	for _, s := range w.GetAdjacentSettlements(x, y) {
+		if !s.Completed {
+			settlements = append(settlements, s)
+		}
+	}
+
+	return settlements
+}
@@ -0,0 +1,353 @@
+package sim
+
+import (
+	"fmt"
+	"image"
+	"math"
+	"sort"
+)
+
+type WorldShape int
+
+const (
+	// ShapeIsland is one lump of land surrounded by water
+	ShapeIsland WorldShape = iota
+	// ShapeContinent is mostly land that runs off the edges of the map
+	ShapeContinent
+	// ShapeArchipelago is lots of small islands
+	ShapeArchipelago
+)
+
+// maxWorldGenAttempts is how many worlds we'll generate looking for one with
+// 	somewhere to spawn before giving up
+const maxWorldGenAttempts = 20
+
+var worldShapeNames = map[WorldShape]string{
+	ShapeIsland:      "island",
+	ShapeContinent:   "continent",
+	ShapeArchipelago: "archipelago",
+}
+
+func (s WorldShape) String() string {
+	return worldShapeNames[s]
+}
+
+// ParseWorldShape returns the shape with the given name
+func ParseWorldShape(name string) (WorldShape, error) {
+	for k, v := range worldShapeNames {
+		if v == name {
+			return k, nil
+		}
+	}
+	return ShapeIsland, fmt.Errorf("unknown world shape '%s'", name)
+}
+
+// WorldOptions are the parameters for generating a world
+type WorldOptions struct {
+	Width  int
+	Height int
+	Shape  WorldShape
+	// WaterLevel is the fraction of the map that is water, 0-1
+	WaterLevel float64
+	// ForestDensity is roughly the fraction of land that is forest, 0-1
+	ForestDensity float64
+	// FishDensity is the chance of coastal water having fish, 0-1
+	FishDensity float64
+	// Relief is how many steps above GrassHeight hills can reach
+	Relief int
+}
+
+// DefaultWorldOptions fits the window without scrolling
+func DefaultWorldOptions() WorldOptions {
+	return WorldOptions{
+		Width:         8,
+		Height:        8,
+		Shape:         ShapeIsland,
+		WaterLevel:    0.45,
+		ForestDensity: 0.15,
+		FishDensity:   0.4,
+		Relief:        3,
+	}
+}
+
+func (o WorldOptions) Validate() error {
+	if o.Width < 2 || o.Height < 2 {
+		return fmt.Errorf("world must be at least 2x2, not %dx%d", o.Width, o.Height)
+	}
+	if o.WaterLevel < 0 || o.WaterLevel >= 1 {
+		return fmt.Errorf("water level must be from 0 up to 1, not %f", o.WaterLevel)
+	}
+	if o.ForestDensity < 0 || o.ForestDensity > 1 {
+		return fmt.Errorf("forest density must be from 0 to 1, not %f", o.ForestDensity)
+	}
+	if o.FishDensity < 0 || o.FishDensity > 1 {
+		return fmt.Errorf("fish density must be from 0 to 1, not %f", o.FishDensity)
+	}
+	if o.Relief < 0 {
+		return fmt.Errorf("relief must not be negative")
+	}
+	return nil
+}
+
+// noise is value noise: random values on a coarse lattice, smoothly
+// 	interpolated for every point in between
+type noise struct {
+	lattice [][]float64
+	scale   float64
+}
+
+func createNoise(rng *Rng, width, height int, scale float64) *noise {
+
+	lw := int(float64(width)/scale) + 2
+	lh := int(float64(height)/scale) + 2
+
+	lattice := make([][]float64, lw)
+	for x := 0; x < lw; x++ {
+		lattice[x] = make([]float64, lh)
+		for y := 0; y < lh; y++ {
+			lattice[x][y] = rng.Float64()
+		}
+	}
+
+	return &noise{
+		lattice: lattice,
+		scale:   scale,
+	}
+}
+
+func smoothstep(t float64) float64 {
+	return t * t * (3 - 2*t)
+}
+
+func lerp(a, b, t float64) float64 {
+	return a + (b-a)*t
+}
+
+func (n *noise) at(x, y int) float64 {
+
+	fx := float64(x) / n.scale
+	fy := float64(y) / n.scale
+	ix := int(fx)
+	iy := int(fy)
+	tx := smoothstep(fx - float64(ix))
+	ty := smoothstep(fy - float64(iy))
+
+	top := lerp(n.lattice[ix][iy], n.lattice[ix+1][iy], tx)
+	bottom := lerp(n.lattice[ix][iy+1], n.lattice[ix+1][iy+1], tx)
+
+	return lerp(top, bottom, ty)
+}
+
+// fractalNoise layers octaves of noise, each half the scale and half the
+// 	weight of the last, and normalises the result to 0-1
+func fractalNoise(rng *Rng, width, height int, scale float64, octaves int) [][]float64 {
+
+	layers := []*noise{}
+	for i := 0; i < octaves && scale >= 1; i++ {
+		layers = append(layers, createNoise(rng, width, height, scale))
+		scale /= 2
+	}
+
+	field := make([][]float64, width)
+	for x := 0; x < width; x++ {
+		field[x] = make([]float64, height)
+		for y := 0; y < height; y++ {
+			weight := 1.0
+			for _, l := range layers {
+				field[x][y] += l.at(x, y) * weight
+				weight /= 2
+			}
+		}
+	}
+
+	normalise(field)
+	return field
+}
+
+// normalise stretches the values in field to fill 0-1
+func normalise(field [][]float64) {
+
+	min, max := math.Inf(1), math.Inf(-1)
+	for x := range field {
+		for y := range field[x] {
+			min = math.Min(min, field[x][y])
+			max = math.Max(max, field[x][y])
+		}
+	}
+
+	for x := range field {
+		for y := range field[x] {
+			if max > min {
+				field[x][y] = (field[x][y] - min) / (max - min)
+			} else {
+				field[x][y] = 0
+			}
+		}
+	}
+}
+
+// falloff is how much to sink the edges of the map for each shape and how
+// 	coarse the noise is relative to the map size
+func (s WorldShape) falloff() (strength float64, scale float64) {
+	switch s {
+	case ShapeContinent:
+		return 0.3, 0.5
+	case ShapeArchipelago:
+		return 0.5, 0.15
+	default:
+		return 2.0, 0.4
+	}
+}
+
+// GenerateWorldTiles generates terrain for a world. Elevation is noise
+// 	sunk towards the edges depending on the shape. The lowest squares, as
+// 	many as the water level asks for, are flooded and the rest are grass
+// 	rising into hills
+func GenerateWorldTiles(rng *Rng, opts WorldOptions) [][]Square {
+
+	w, h := opts.Width, opts.Height
+	strength, scale := opts.Shape.falloff()
+	size := float64(w)
+	if h > w {
+		size = float64(h)
+	}
+
+	elevation := fractalNoise(rng, w, h, math.Max(size*scale, 1), 3)
+	forest := fractalNoise(rng, w, h, math.Max(size*0.25, 1), 2)
+
+	cx, cy := float64(w-1)/2, float64(h-1)/2
+	for x := 0; x < w; x++ {
+		for y := 0; y < h; y++ {
+			dx := (float64(x) - cx) / math.Max(cx, 1)
+			dy := (float64(y) - cy) / math.Max(cy, 1)
+			d := math.Min(math.Sqrt(dx*dx+dy*dy)/math.Sqrt2, 1)
+			elevation[x][y] -= d * d * strength
+		}
+	}
+	normalise(elevation)
+	sea := seaLevel(elevation, opts.WaterLevel)
+
+	tiles := make([][]Square, w)
+	for x := 0; x < w; x++ {
+		tiles[x] = make([]Square, h)
+		for y := 0; y < h; y++ {
+
+			e := elevation[x][y]
+			if e < sea {
+				tiles[x][y] = CreateWater()
+				continue
+			}
+
+			if forest[x][y] > 1-opts.ForestDensity {
+				tiles[x][y] = CreateWoods()
+			} else {
+				tiles[x][y] = CreateGrass()
+			}
+
+			// lowlands sit a step below default grass, heights go up in twos
+			t := 0.0
+			if sea < 1 {
+				t = (e - sea) / (1 - sea)
+			}
+			step := int(t * float64(opts.Relief+1))
+			if step > opts.Relief {
+				step = opts.Relief
+			}
+			tiles[x][y].Height += (step * 2) - 2
+		}
+	}
+
+	// everything else is scattered at random
+	for _, id := range ResourceTypeIDs() {
+		rt := ResourceTypes[id]
+		if rt.SpawnChance <= 0 {
+			continue
+		}
+		for x := 0; x < w; x++ {
+			for y := 0; y < h; y++ {
+				square := &tiles[x][y]
+				if square.Kind == rt.SpawnOn && square.IsEmpty() && rng.Float64() < rt.SpawnChance {
+					square.PlaceResource(rt)
+				}
+			}
+		}
+	}
+
+	// fish live in shallow water, next to land
+	world := &World{Squares: tiles}
+	for x := 0; x < w; x++ {
+		for y := 0; y < h; y++ {
+			if tiles[x][y].Kind != TWater {
+				continue
+			}
+			coastal := false
+			for _, n := range world.Neighbours(x, y) {
+				if tiles[n.X][n.Y].Kind == TGrass {
+					coastal = true
+				}
+			}
+			if coastal && rng.Float64() < opts.FishDensity {
+				tiles[x][y] = CreateFishery()
+			}
+		}
+	}
+
+	return tiles
+}
+
+// seaLevel returns the elevation below which the given fraction of the
+// 	field lies
+func seaLevel(field [][]float64, fraction float64) float64 {
+
+	all := []float64{}
+	for x := range field {
+		all = append(all, field[x]...)
+	}
+	sort.Float64s(all)
+
+	i := int(fraction * float64(len(all)))
+	if i >= len(all) {
+		return math.Inf(1)
+	}
+	return all[i]
+}
+
+// FindSpawnPoint picks the empty grass square with the most land around it
+// 	to build on, preferring the middle of the map. Returns false if there
+// 	is nowhere with room to grow
+func FindSpawnPoint(squares [][]Square) (image.Point, bool) {
+
+	w := &World{Squares: squares}
+	cx, cy := float64(len(squares)-1)/2, float64(len(squares[0])-1)/2
+
+	best := image.Point{X: -1, Y: -1}
+	bestScore := math.Inf(-1)
+
+	for x := 0; x < len(squares); x++ {
+		for y := 0; y < len(squares[x]); y++ {
+
+			square := &squares[x][y]
+			if square.Kind != TGrass || !square.IsEmpty() {
+				continue
+			}
+
+			land := 0
+			for _, n := range w.Neighbours(x, y) {
+				if squares[n.X][n.Y].Kind == TGrass {
+					land++
+				}
+			}
+			if land == 0 {
+				continue
+			}
+
+			score := float64(land)*100 - math.Hypot(float64(x)-cx, float64(y)-cy)
+			if score > bestScore {
+				best = image.Point{X: x, Y: y}
+				bestScore = score
+			}
+		}
+	}
+
+	return best, best.X >= 0
+}